@@ -1,12 +1,28 @@
 package address
 
 import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
 	"github.com/google/uuid"
+	"github.com/quantosnetwork/Quantos/crypto"
+	"github.com/quantosnetwork/Quantos/sdk/config"
 	"go.uber.org/atomic"
 )
 
+// DefaultAccountPath is the HD path of the signing key of an account.
+const DefaultAccountPath = "m/44'/906'/0'/0/0"
+
+var ErrAccountNoKeystore = errors.New("quantos account: account has no keystore")
+
 type Address interface{}
 
+// Account is a wallet address and, for accounts we hold the keys of, the
+// keystore its seed is encrypted in. Lock is true whenever the seed is not
+// in memory.
 type Account struct {
 	ID             uuid.UUID
 	address        *Address
@@ -15,15 +31,210 @@ type Account struct {
 	Lock           atomic.Bool
 	Wallet         interface{}
 	CreatedAtBlock uint32
+	// Multisig is the signer policy of an m-of-n account, nil for single
+	// key accounts.
+	Multisig *MultisigPolicy
+	// KeyFile is the path of the linked keystore file, relative to the
+	// account store.
+	KeyFile string
+
+	mu       sync.Mutex
+	keystore *Keystore
+	relock   *time.Timer
+	// unlocks counts Unlock calls, a relock timer only fires for the
+	// unlock that started it
+	unlocks uint64
+}
+
+// NewAccount returns the account of an HD seed: its address is the wallet
+// address of the key at DefaultAccountPath.
+func NewAccount(network config.NetworkID, seed []byte) (*Account, error) {
+	keys, err := accountKeys(seed)
+	if err != nil {
+		return nil, err
+	}
+	pk, err := keys.PubKey.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	addr, err := FromPublicKey(network, pk)
+	if err != nil {
+		return nil, err
+	}
+	a := &Account{ID: uuid.New(), Address: addr.String()}
+	a.Lock.Store(true)
+	return a, nil
+}
+
+// NewMultisigAccount returns the account of a version 0 multisig policy.
+func NewMultisigAccount(network config.NetworkID, policy *MultisigPolicy) (*Account, error) {
+	addr, err := policy.Address(network)
+	if err != nil {
+		return nil, err
+	}
+	a := &Account{
+		ID:       uuid.New(),
+		Address:  addr.String(),
+		Multisig: policy,
+	}
+	a.Lock.Store(true)
+	return a, nil
+}
+
+func accountKeys(seed []byte) (*crypto.HardenedKeys, error) {
+	master, err := crypto.NewMasterExtendedKey(seed)
+	if err != nil {
+		return nil, err
+	}
+	path, _ := crypto.ParseDerivationPath(DefaultAccountPath)
+	child, err := master.DerivePath(path)
+	if err != nil {
+		return nil, err
+	}
+	return child.HardenedKeys()
+}
+
+// LinkKeystore attaches the keystore holding the seed of the account.
+func (a *Account) LinkKeystore(ks *Keystore) error {
+	if ks.Address() != a.Address {
+		return errors.New("quantos account: keystore is for another address")
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.keystore = ks
+	return nil
+}
+
+func (a *Account) Keystore() *Keystore {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.keystore
+}
+
+// Unlock decrypts the seed of the account. With a positive timeout the
+// account locks itself again once it expires.
+func (a *Account) Unlock(passphrase string, timeout time.Duration) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.keystore == nil {
+		return ErrAccountNoKeystore
+	}
+	if err := a.keystore.Unlock(passphrase); err != nil {
+		return err
+	}
+	if a.relock != nil {
+		a.relock.Stop()
+		a.relock = nil
+	}
+	a.unlocks++
+	if timeout > 0 {
+		unlock := a.unlocks
+		a.relock = time.AfterFunc(timeout, func() { a.expire(unlock) })
+	}
+	a.Lock.Store(false)
+	return nil
+}
+
+// expire relocks the account unless it was unlocked again after unlock,
+// Stop does not catch a timer already waiting for the lock.
+func (a *Account) expire(unlock uint64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.unlocks == unlock {
+		a.relockLocked()
+	}
+}
+
+// Relock wipes the seed from memory.
+func (a *Account) Relock() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.relockLocked()
+}
+
+func (a *Account) relockLocked() {
+	if a.relock != nil {
+		a.relock.Stop()
+		a.relock = nil
+	}
+	if a.keystore != nil {
+		a.keystore.Relock()
+	}
+	a.Lock.Store(true)
+}
+
+// MasterKey returns the HD root of an unlocked account.
+func (a *Account) MasterKey() (*crypto.ExtendedKey, error) {
+	ks := a.Keystore()
+	if ks == nil {
+		return nil, ErrAccountNoKeystore
+	}
+	seed, err := ks.Secret()
+	if err != nil {
+		return nil, err
+	}
+	return crypto.NewMasterExtendedKey(seed)
+}
+
+// Keys returns the signing keys of an unlocked account.
+func (a *Account) Keys() (*crypto.HardenedKeys, error) {
+	ks := a.Keystore()
+	if ks == nil {
+		return nil, ErrAccountNoKeystore
+	}
+	seed, err := ks.Secret()
+	if err != nil {
+		return nil, err
+	}
+	return accountKeys(seed)
+}
+
+type accountJSON struct {
+	ID             uuid.UUID `json:"id"`
+	Address        string    `json:"address"`
+	CreatedAtBlock uint32    `json:"createdAtBlock"`
+	KeyFile        string    `json:"keyfile,omitempty"`
+	Multisig       string    `json:"multisig,omitempty"`
+}
+
+func (a *Account) MarshalJSON() ([]byte, error) {
+	j := accountJSON{
+		ID:             a.ID,
+		Address:        a.Address,
+		CreatedAtBlock: a.CreatedAtBlock,
+		KeyFile:        a.KeyFile,
+	}
+	if a.Multisig != nil {
+		j.Multisig = hex.EncodeToString(a.Multisig.Bytes())
+	}
+	return json.Marshal(j)
 }
 
-type account interface {
-	New() *Account
-	GetWalletAddress() *string
-	Unlock() bool
-	Lock() bool
-	GetAllTransactions() map[string]interface{}
-	GetOneTransaction(txid string) interface{}
+func (a *Account) UnmarshalJSON(b []byte) error {
+	var j accountJSON
+	if err := json.Unmarshal(b, &j); err != nil {
+		return err
+	}
+	addr, err := ParseAddress(j.Address)
+	if err != nil {
+		return err
+	}
+	a.ID = j.ID
+	// canonical, the account store is keyed by it
+	a.Address = addr.String()
+	a.CreatedAtBlock = j.CreatedAtBlock
+	a.KeyFile = j.KeyFile
+	if j.Multisig != "" {
+		raw, err := hex.DecodeString(j.Multisig)
+		if err != nil {
+			return err
+		}
+		if a.Multisig, err = ParseMultisigPolicy(raw); err != nil {
+			return err
+		}
+	}
+	a.Lock.Store(true)
+	return nil
 }
 
 type AccountState struct {
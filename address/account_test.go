@@ -0,0 +1,86 @@
+package address
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/quantosnetwork/Quantos/sdk/config"
+)
+
+func testAccount(t *testing.T) *Account {
+	t.Helper()
+	seed := bytes.Repeat([]byte{0x2a}, 32)
+	a, err := NewAccount(config.TESTNET, seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	k, err := EncryptKeyWithParams(seed, a.Address, "pass", testKDFParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := a.LinkKeystore(NewKeystore(k)); err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+// TestAccountStaleRelock fires the timer of an earlier unlock after a new
+// one, as happens when it was already waiting for the lock.
+func TestAccountStaleRelock(t *testing.T) {
+	a := testAccount(t)
+	if err := a.Unlock("pass", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	stale := a.unlocks
+	if err := a.Unlock("pass", 0); err != nil {
+		t.Fatal(err)
+	}
+	a.expire(stale)
+	if a.Lock.Load() {
+		t.Fatal("a stale timer locked the account")
+	}
+	if _, err := a.Keys(); err != nil {
+		t.Fatal(err)
+	}
+	a.expire(a.unlocks)
+	if !a.Lock.Load() {
+		t.Fatal("the current timer did not lock the account")
+	}
+}
+
+func TestAccountStoreKeyFile(t *testing.T) {
+	for _, name := range []string{"../../x.key", "sub/x.key", "..", "/etc/passwd"} {
+		dir := t.TempDir()
+		b, _ := json.Marshal(map[string]string{"address": ZeroAddressOf(config.TESTNET), "keyfile": name})
+		if err := ioutil.WriteFile(filepath.Join(dir, "a.json"), b, 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := OpenAccountStore(dir); err != ErrAccountKeyFile {
+			t.Fatalf("key file %q: %v", name, err)
+		}
+	}
+}
+
+func TestAccountStoreGet(t *testing.T) {
+	s, err := OpenAccountStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := testAccount(t)
+	if err := s.Save(a); err != nil {
+		t.Fatal(err)
+	}
+	for _, addr := range []string{a.Address, strings.ToUpper(a.Address)} {
+		if got, err := s.Get(addr); err != nil || got != a {
+			t.Fatalf("get %s: %v", addr, err)
+		}
+	}
+	if _, err := s.Get("qbit"); err != ErrAccountNotFound {
+		t.Fatalf("invalid address: %v", err)
+	}
+}
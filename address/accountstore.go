@@ -0,0 +1,144 @@
+package address
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+/*
+
+	Account store
+
+	A directory holding one <account id>.json file per account and, for
+	accounts with keys, the keystore file it links to:
+
+	accounts/
+	  7d3c...e1.json       {"id": ..., "address": ..., "keyfile": "7d3c...e1.key"}
+	  7d3c...e1.key        keystore file, see keystore.go
+
+*/
+
+var (
+	ErrAccountNotFound = errors.New("quantos account: account not found")
+	ErrAccountKeyFile  = errors.New("quantos account: key file outside the account store")
+)
+
+type AccountStore struct {
+	dir       string
+	KDFParams KDFParams
+
+	mu       sync.RWMutex
+	accounts map[string]*Account
+}
+
+// OpenAccountStore loads every account of dir, creating it if needed.
+func OpenAccountStore(dir string) (*AccountStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	s := &AccountStore{dir: dir, KDFParams: DefaultKDFParams, accounts: make(map[string]*Account)}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), ".json") {
+			continue
+		}
+		a, err := s.load(f.Name())
+		if err != nil {
+			return nil, err
+		}
+		s.accounts[a.Address] = a
+	}
+	return s, nil
+}
+
+func (s *AccountStore) load(name string) (*Account, error) {
+	b, err := ioutil.ReadFile(filepath.Join(s.dir, name))
+	if err != nil {
+		return nil, err
+	}
+	a := &Account{}
+	if err := json.Unmarshal(b, a); err != nil {
+		return nil, err
+	}
+	if a.KeyFile != "" {
+		// a plain file name, the account file must not point elsewhere
+		if filepath.Base(a.KeyFile) != a.KeyFile || a.KeyFile == "." || a.KeyFile == ".." {
+			return nil, ErrAccountKeyFile
+		}
+		ks, err := OpenKeystore(filepath.Join(s.dir, a.KeyFile))
+		if err != nil {
+			return nil, err
+		}
+		if err := a.LinkKeystore(ks); err != nil {
+			return nil, err
+		}
+	}
+	return a, nil
+}
+
+// Create encrypts seed under passphrase and stores the new account with
+// its keystore.
+func (s *AccountStore) Create(a *Account, seed []byte, passphrase string) error {
+	k, err := EncryptKeyWithParams(seed, a.Address, passphrase, s.KDFParams)
+	if err != nil {
+		return err
+	}
+	a.KeyFile = a.ID.String() + ".key"
+	if err := SaveKeyFile(filepath.Join(s.dir, a.KeyFile), k); err != nil {
+		return err
+	}
+	if err := a.LinkKeystore(NewKeystore(k)); err != nil {
+		return err
+	}
+	return s.Save(a)
+}
+
+// Save writes the account file, e.g. for watch-only and multisig accounts.
+func (s *AccountStore) Save(a *Account) error {
+	b, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(s.dir, a.ID.String()+".json"), b, 0600); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.accounts[a.Address] = a
+	s.mu.Unlock()
+	return nil
+}
+
+// Get returns the account of addr, in any case.
+func (s *AccountStore) Get(addr string) (*Account, error) {
+	parsed, err := ParseAddress(addr)
+	if err != nil {
+		return nil, ErrAccountNotFound
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	a, ok := s.accounts[parsed.String()]
+	if !ok {
+		return nil, ErrAccountNotFound
+	}
+	return a, nil
+}
+
+func (s *AccountStore) Accounts() []*Account {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]*Account, 0, len(s.accounts))
+	for _, a := range s.accounts {
+		out = append(out, a)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Address < out[j].Address })
+	return out
+}
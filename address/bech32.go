@@ -0,0 +1,147 @@
+package address
+
+import (
+	"errors"
+	"strings"
+)
+
+// Bech32m (BIP350) as used by the QBIT address encoding. The BCH checksum
+// detects any error affecting up to 4 characters and makes other typos
+// undetected with a probability below 1 in 10^9.
+
+const (
+	bech32Charset   = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+	bech32mConst    = 0x2bc830a3
+	bech32ChecksumN = 6
+	bech32MaxLength = 90
+)
+
+var (
+	errBech32Length   = errors.New("quantos address: invalid address length")
+	errBech32Case     = errors.New("quantos address: mixed case address")
+	errBech32Char     = errors.New("quantos address: invalid character")
+	errBech32Checksum = errors.New("quantos address: checksum mismatch")
+	errBech32Padding  = errors.New("quantos address: invalid padding")
+)
+
+var bech32Gen = [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+func bech32Polymod(values []byte) uint32 {
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= bech32Gen[i]
+			}
+		}
+	}
+	return chk
+}
+
+func bech32HRPExpand(hrp string) []byte {
+	out := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]>>5)
+	}
+	out = append(out, 0)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]&31)
+	}
+	return out
+}
+
+func bech32Checksum(hrp string, data []byte) []byte {
+	values := append(bech32HRPExpand(hrp), data...)
+	values = append(values, make([]byte, bech32ChecksumN)...)
+	mod := bech32Polymod(values) ^ bech32mConst
+	out := make([]byte, bech32ChecksumN)
+	for i := range out {
+		out[i] = byte(mod>>uint(5*(5-i))) & 31
+	}
+	return out
+}
+
+// bech32Encode encodes 8 bit data under the human readable part hrp.
+func bech32Encode(hrp string, data []byte) (string, error) {
+	values, err := convertBits(data, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+	values = append(values, bech32Checksum(hrp, values)...)
+	if len(hrp)+1+len(values) > bech32MaxLength {
+		return "", errBech32Length
+	}
+	var sb strings.Builder
+	sb.WriteString(hrp)
+	sb.WriteByte('1')
+	for _, v := range values {
+		sb.WriteByte(bech32Charset[v])
+	}
+	return sb.String(), nil
+}
+
+// bech32Decode returns the human readable part and the 8 bit data of s.
+func bech32Decode(s string) (string, []byte, error) {
+	if len(s) > bech32MaxLength {
+		return "", nil, errBech32Length
+	}
+	lower := strings.ToLower(s)
+	if lower != s && strings.ToUpper(s) != s {
+		return "", nil, errBech32Case
+	}
+	s = lower
+	pos := strings.LastIndexByte(s, '1')
+	if pos < 1 || pos+bech32ChecksumN+1 > len(s) {
+		return "", nil, errBech32Length
+	}
+	hrp := s[:pos]
+	for i := 0; i < len(hrp); i++ {
+		if hrp[i] < 33 || hrp[i] > 126 {
+			return "", nil, errBech32Char
+		}
+	}
+	values := make([]byte, 0, len(s)-pos-1)
+	for i := pos + 1; i < len(s); i++ {
+		v := strings.IndexByte(bech32Charset, s[i])
+		if v < 0 {
+			return "", nil, errBech32Char
+		}
+		values = append(values, byte(v))
+	}
+	if bech32Polymod(append(bech32HRPExpand(hrp), values...)) != bech32mConst {
+		return "", nil, errBech32Checksum
+	}
+	data, err := convertBits(values[:len(values)-bech32ChecksumN], 5, 8, false)
+	if err != nil {
+		return "", nil, err
+	}
+	return hrp, data, nil
+}
+
+func convertBits(data []byte, from, to uint, pad bool) ([]byte, error) {
+	acc := uint32(0)
+	bits := uint(0)
+	maxv := uint32(1)<<to - 1
+	out := make([]byte, 0, len(data)*int(from)/int(to)+1)
+	for _, b := range data {
+		if uint32(b)>>from != 0 {
+			return nil, errBech32Char
+		}
+		acc = acc<<from | uint32(b)
+		bits += from
+		for bits >= to {
+			bits -= to
+			out = append(out, byte(acc>>bits&maxv))
+		}
+	}
+	if pad {
+		if bits > 0 {
+			out = append(out, byte(acc<<(to-bits)&maxv))
+		}
+	} else if bits >= from || acc<<(to-bits)&maxv != 0 {
+		return nil, errBech32Padding
+	}
+	return out, nil
+}
@@ -0,0 +1,220 @@
+package address
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"strings"
+
+	"github.com/quantosnetwork/Quantos/sdk/config"
+	"github.com/tyler-smith/go-bip39/wordlists"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
+	"lukechampine.com/frand"
+)
+
+/*
+
+	BIP39 mnemonics
+
+	entropy (128 to 256 bits, multiple of 32)
+	checksum = first ENT/32 bits of SHA256(entropy)
+	words = (entropy || checksum) split in 11 bits indexes into a 2048 words list
+
+	seed = PBKDF2-HMAC-SHA512(NFKD(mnemonic), "mnemonic"+NFKD(passphrase), 2048, 64)
+
+	The 64 bytes seed is the HD seed of an account (see NewAccount), so the
+	same words and passphrase always recover the same keys and addresses.
+
+*/
+
+type Language string
+
+const (
+	English            Language = "english"
+	ChineseSimplified  Language = "chinese_simplified"
+	ChineseTraditional Language = "chinese_traditional"
+	Czech              Language = "czech"
+	French             Language = "french"
+	Italian            Language = "italian"
+	Japanese           Language = "japanese"
+	Korean             Language = "korean"
+	Spanish            Language = "spanish"
+)
+
+const (
+	bip39SeedIterations = 2048
+	bip39SeedLen        = 64
+)
+
+var (
+	ErrInvalidMnemonic  = errors.New("quantos bip39: invalid mnemonic")
+	ErrMnemonicChecksum = errors.New("quantos bip39: mnemonic checksum mismatch")
+	ErrEntropyLength    = errors.New("quantos bip39: entropy must be 128 to 256 bits and a multiple of 32")
+	ErrUnknownLanguage  = errors.New("quantos bip39: unknown mnemonic language")
+)
+
+type wordlist struct {
+	words []string
+	index map[string]int
+}
+
+var bip39Wordlists = map[Language]*wordlist{}
+
+func init() {
+	for lang, words := range map[Language][]string{
+		English:            wordlists.English,
+		ChineseSimplified:  wordlists.ChineseSimplified,
+		ChineseTraditional: wordlists.ChineseTraditional,
+		Czech:              wordlists.Czech,
+		French:             wordlists.French,
+		Italian:            wordlists.Italian,
+		Japanese:           wordlists.Japanese,
+		Korean:             wordlists.Korean,
+		Spanish:            wordlists.Spanish,
+	} {
+		wl := &wordlist{words: words, index: make(map[string]int, len(words))}
+		for i, w := range words {
+			wl.index[norm.NFKD.String(w)] = i
+		}
+		bip39Wordlists[lang] = wl
+	}
+}
+
+func getWordlist(lang Language) (*wordlist, error) {
+	wl, ok := bip39Wordlists[lang]
+	if !ok {
+		return nil, ErrUnknownLanguage
+	}
+	return wl, nil
+}
+
+// NewMnemonic generates a mnemonic of 12, 15, 18, 21 or 24 words.
+func NewMnemonic(words int, lang Language) (string, error) {
+	if words%3 != 0 {
+		return "", ErrEntropyLength
+	}
+	return MnemonicFromEntropy(frand.Bytes(words/3*4), lang)
+}
+
+func MnemonicFromEntropy(entropy []byte, lang Language) (string, error) {
+	wl, err := getWordlist(lang)
+	if err != nil {
+		return "", err
+	}
+	bits := len(entropy) * 8
+	if bits < 128 || bits > 256 || bits%32 != 0 {
+		return "", ErrEntropyLength
+	}
+	h := sha256.Sum256(entropy)
+	// entropy || checksum, the checksum is at most 8 bits
+	data := append(append([]byte(nil), entropy...), h[0])
+	count := (bits + bits/32) / 11
+	words := make([]string, count)
+	for i := 0; i < count; i++ {
+		words[i] = wl.words[readBits11(data, i*11)]
+	}
+	sep := " "
+	if lang == Japanese {
+		sep = "　"
+	}
+	return strings.Join(words, sep), nil
+}
+
+// EntropyFromMnemonic recovers the entropy of a mnemonic and checks its
+// checksum.
+func EntropyFromMnemonic(mnemonic string, lang Language) ([]byte, error) {
+	wl, err := getWordlist(lang)
+	if err != nil {
+		return nil, err
+	}
+	words := strings.Fields(norm.NFKD.String(mnemonic))
+	if len(words) < 12 || len(words) > 24 || len(words)%3 != 0 {
+		return nil, ErrInvalidMnemonic
+	}
+	bits := len(words) * 11
+	entropyBits := bits * 32 / 33
+	data := make([]byte, (bits+7)/8)
+	for i, w := range words {
+		idx, ok := wl.index[w]
+		if !ok {
+			return nil, ErrInvalidMnemonic
+		}
+		writeBits11(data, i*11, idx)
+	}
+	entropy := data[:entropyBits/8]
+	h := sha256.Sum256(entropy)
+	csBits := uint(entropyBits / 32)
+	mask := byte(0xff << (8 - csBits))
+	if data[entropyBits/8]&mask != h[0]&mask {
+		return nil, ErrMnemonicChecksum
+	}
+	return entropy, nil
+}
+
+// MnemonicLanguage returns the first language whose wordlist contains every
+// word of the mnemonic with a valid checksum.
+func MnemonicLanguage(mnemonic string) (Language, error) {
+	for _, lang := range []Language{English, Spanish, French, Italian, Czech, Japanese, Korean, ChineseSimplified, ChineseTraditional} {
+		if _, err := EntropyFromMnemonic(mnemonic, lang); err == nil {
+			return lang, nil
+		}
+	}
+	return "", ErrInvalidMnemonic
+}
+
+func IsMnemonicValid(mnemonic string) bool {
+	_, err := MnemonicLanguage(mnemonic)
+	return err == nil
+}
+
+// SeedFromMnemonic validates the mnemonic and derives its 64 bytes seed.
+func SeedFromMnemonic(mnemonic string, passphrase string) ([]byte, error) {
+	if !IsMnemonicValid(mnemonic) {
+		return nil, ErrInvalidMnemonic
+	}
+	return NewSeed(mnemonic, passphrase), nil
+}
+
+// NewSeed derives the BIP39 seed without validating the mnemonic.
+func NewSeed(mnemonic string, passphrase string) []byte {
+	m := strings.Join(strings.Fields(norm.NFKD.String(mnemonic)), " ")
+	salt := "mnemonic" + norm.NFKD.String(passphrase)
+	return pbkdf2.Key([]byte(m), []byte(salt), bip39SeedIterations, bip39SeedLen, sha512.New)
+}
+
+// AccountFromMnemonic recovers the account of a mnemonic and its seed, to
+// be stored in a keystore. The keys derived from the seed sign for the
+// account address.
+func AccountFromMnemonic(network config.NetworkID, mnemonic string, passphrase string) (*Account, []byte, error) {
+	seed, err := SeedFromMnemonic(mnemonic, passphrase)
+	if err != nil {
+		return nil, nil, err
+	}
+	a, err := NewAccount(network, seed)
+	if err != nil {
+		return nil, nil, err
+	}
+	return a, seed, nil
+}
+
+func readBits11(data []byte, pos int) int {
+	v := 0
+	for i := 0; i < 11; i++ {
+		b := pos + i
+		v <<= 1
+		if data[b/8]&(0x80>>uint(b%8)) != 0 {
+			v |= 1
+		}
+	}
+	return v
+}
+
+func writeBits11(data []byte, pos int, v int) {
+	for i := 0; i < 11; i++ {
+		if v&(1<<uint(10-i)) != 0 {
+			b := pos + i
+			data[b/8] |= 0x80 >> uint(b%8)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package address
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/quantosnetwork/Quantos/sdk/config"
+)
+
+// vectors from the BIP39 reference implementation, passphrase "TREZOR"
+var bip39Vectors = []struct {
+	entropy  string
+	mnemonic string
+	seed     string
+}{
+	{
+		"00000000000000000000000000000000",
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+		"c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+	},
+	{
+		"7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+		"legal winner thank year wave sausage worth useful legal winner thank yellow",
+		"2e8905819b8723fe2c1d161860e5ee1830318dbf49a83bd451cfb8440c28bd6fa457fe1296106559a3c80937a1c1069be3a3a5bd381ee6260e8d9739fce1f607",
+	},
+	{
+		"ffffffffffffffffffffffffffffffff",
+		"zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo wrong",
+		"ac27495480225222079d7be181583751e86f571027b0497b5b5d11218e0a8a13332572917f0f8e5a589620c6f15b11c61dee327651a14c34e18231052e48c069",
+	},
+	{
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon art",
+		"bda85446c68413707090a52022edd26a1c9462295029f2e60cd7c4f2bbd3097170af7a4d73245cafa9c3cca8d561a7c3de6f5d4a10be8ed2a5e608d68f92fcc8",
+	},
+}
+
+func TestBIP39Vectors(t *testing.T) {
+	for _, v := range bip39Vectors {
+		entropy, _ := hex.DecodeString(v.entropy)
+		m, err := MnemonicFromEntropy(entropy, English)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if m != v.mnemonic {
+			t.Fatalf("got mnemonic %q, want %q", m, v.mnemonic)
+		}
+		back, err := EntropyFromMnemonic(m, English)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(back, entropy) {
+			t.Fatalf("entropy round trip failed for %q", m)
+		}
+		seed, err := SeedFromMnemonic(m, "TREZOR")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(seed) != v.seed {
+			t.Fatalf("got seed %x, want %s", seed, v.seed)
+		}
+	}
+}
+
+func TestBIP39Checksum(t *testing.T) {
+	if _, err := EntropyFromMnemonic("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon", English); err != ErrMnemonicChecksum {
+		t.Fatalf("expected ErrMnemonicChecksum, got %v", err)
+	}
+	if IsMnemonicValid("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon quantos") {
+		t.Fatal("a mnemonic with an unknown word is valid")
+	}
+}
+
+func TestBIP39Languages(t *testing.T) {
+	for lang := range bip39Wordlists {
+		for _, words := range []int{12, 24} {
+			m, err := NewMnemonic(words, lang)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := MnemonicLanguage(m)
+			if err != nil {
+				t.Fatalf("%s: %v", lang, err)
+			}
+			if _, err := EntropyFromMnemonic(m, got); err != nil {
+				t.Fatalf("%s: %v", lang, err)
+			}
+		}
+	}
+}
+
+func TestAccountFromMnemonic(t *testing.T) {
+	m, err := NewMnemonic(24, English)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, seed, err := AccountFromMnemonic(config.TESTNET, m, "pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _, err := AccountFromMnemonic(config.TESTNET, m, "pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Address != b.Address {
+		t.Fatal("the same mnemonic recovered different addresses")
+	}
+	c, _, _ := AccountFromMnemonic(config.TESTNET, m, "other")
+	if a.Address == c.Address {
+		t.Fatal("the passphrase does not change the address")
+	}
+
+	// the recovered seed holds the keys of the address
+	k, err := EncryptKeyWithParams(seed, a.Address, "pass", testKDFParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := a.LinkKeystore(NewKeystore(k)); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Unlock("pass", 0); err != nil {
+		t.Fatal(err)
+	}
+	keys, err := a.Keys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, _ := keys.PubKey.MarshalBinary()
+	if addr, _ := FromPublicKey(config.TESTNET, pk); addr.String() != a.Address {
+		t.Fatal("the recovered keys do not control the address")
+	}
+}
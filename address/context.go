@@ -1,5 +1,11 @@
 package address
 
+import (
+	"errors"
+
+	"github.com/quantosnetwork/Quantos/crypto"
+)
+
 type Purpose uint32
 
 const (
@@ -37,3 +43,55 @@ type AddressContext struct {
 	signedTimestamp string
 	signature       string
 }
+
+// NewAddressContext returns the BIP44 style context
+// m/purpose'/906'/account'/change/index of a QTO address.
+func NewAddressContext(purpose Purpose, account, change, index uint32) *AddressContext {
+	c := &AddressContext{
+		purpose:  int(uint32(purpose) &^ Apostrophe),
+		cointype: int(uint32(QTO) &^ Apostrophe),
+		account:  int(account &^ Apostrophe),
+		change:   int(change),
+		index:    int(index),
+	}
+	c.derivationPath = crypto.FormatDerivationPath(c.Path())
+	return c
+}
+
+// AddressContextFromPath parses a derivation path like m/44'/906'/0'/0/5.
+// Purpose, coin type and account must be hardened and the coin type must be
+// QTO.
+func AddressContextFromPath(path string) (*AddressContext, error) {
+	p, err := crypto.ParseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(p) != 5 || p[0] < Apostrophe || p[1] < Apostrophe || p[2] < Apostrophe ||
+		p[3] >= Apostrophe || p[4] >= Apostrophe {
+		return nil, crypto.ErrInvalidDerivationPath
+	}
+	switch Purpose(p[0]) {
+	case PurposeBIP44, PurposeBIP49, PurposeBIP84:
+	default:
+		return nil, errors.New("quantos address: unsupported purpose in " + path)
+	}
+	if p[1] != QTO {
+		return nil, errors.New("quantos address: not a QTO derivation path: " + path)
+	}
+	return NewAddressContext(Purpose(p[0]), p[2], p[3], p[4]), nil
+}
+
+// Path returns the derivation path as child indexes.
+func (c *AddressContext) Path() []uint32 {
+	return []uint32{
+		uint32(c.purpose) | Apostrophe,
+		uint32(c.cointype) | Apostrophe,
+		uint32(c.account) | Apostrophe,
+		uint32(c.change),
+		uint32(c.index),
+	}
+}
+
+func (c *AddressContext) DerivationPath() string {
+	return c.derivationPath
+}
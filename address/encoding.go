@@ -0,0 +1,159 @@
+package address
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/quantosnetwork/Quantos/sdk/config"
+	"github.com/zeebo/blake3"
+)
+
+/*
+
+	QBIT address encoding (version 1)
+
+	[1]byte  format version (1)
+	[2]byte  network id (config.LIVENET, TESTNET, LOCALNET)
+	[1]byte  address type (config.QBIT_ADDRESS_PREFIX, TX_, BLOCK_, CONTRACT_)
+	[32]byte hash of what the address points to
+
+	The 36 bytes are encoded with bech32m under the "qbit" prefix, e.g.
+
+	qbit1q9cq5qg...
+
+	The bech32m checksum rejects typos, the version, network and type bytes
+	are checked by ParseAddress.
+
+*/
+
+const (
+	AddressHRP     = "qbit"
+	AddressVersion = 1
+	AddressHashLen = 32
+
+	encodedAddressLen = 1 + 2 + 1 + AddressHashLen
+)
+
+var (
+	ErrAddressVersion = errors.New("quantos address: unsupported address version")
+	ErrAddressNetwork = errors.New("quantos address: unknown network")
+	ErrAddressType    = errors.New("quantos address: unknown address type")
+	ErrAddressHRP     = errors.New("quantos address: not a qbit address")
+)
+
+type EncodedAddress struct {
+	Version byte
+	Network config.NetworkID
+	Type    uint32
+	Hash    [AddressHashLen]byte
+}
+
+func isKnownNetwork(n config.NetworkID) bool {
+	return n == config.LIVENET || n == config.TESTNET || n == config.LOCALNET
+}
+
+func isKnownAddressType(t uint32) bool {
+	return t >= config.QBIT_ADDRESS_PREFIX && t <= config.CONTRACT_ADDRESS_PREFIX
+}
+
+func NewEncodedAddress(network config.NetworkID, addrType uint32, hash []byte) (*EncodedAddress, error) {
+	if !isKnownNetwork(network) {
+		return nil, ErrAddressNetwork
+	}
+	if !isKnownAddressType(addrType) {
+		return nil, ErrAddressType
+	}
+	if len(hash) != AddressHashLen {
+		return nil, errors.New("quantos address: hash must be 32 bytes")
+	}
+	a := &EncodedAddress{Version: AddressVersion, Network: network, Type: addrType}
+	copy(a.Hash[:], hash)
+	return a, nil
+}
+
+// FromPublicKey returns the wallet address of a public key.
+func FromPublicKey(network config.NetworkID, pk []byte) (*EncodedAddress, error) {
+	h := make([]byte, AddressHashLen)
+	blake3.DeriveKey("qbit-address", pk, h)
+	return NewEncodedAddress(network, config.QBIT_ADDRESS_PREFIX, h)
+}
+
+var contentContexts = map[uint32]string{
+	config.TX_ADDRESS_PREFIX:       "qbit-tx-address",
+	config.BLOCK_ADDRESS_PREFIX:    "qbit-block-address",
+	config.CONTRACT_ADDRESS_PREFIX: "qbit-contract-address",
+}
+
+// FromContent returns the address of a tx, block or contract from its
+// serialised content. Each type hashes under its own blake3 context so
+// the same bytes never give the same address for two types.
+func FromContent(network config.NetworkID, addrType uint32, content []byte) (*EncodedAddress, error) {
+	ctx, ok := contentContexts[addrType]
+	if !ok {
+		return nil, ErrAddressType
+	}
+	h := make([]byte, AddressHashLen)
+	blake3.DeriveKey(ctx, content, h)
+	return NewEncodedAddress(network, addrType, h)
+}
+
+func (a *EncodedAddress) Bytes() []byte {
+	b := make([]byte, 0, encodedAddressLen)
+	b = append(b, a.Version)
+	b = append(b, a.Network[:]...)
+	b = append(b, byte(a.Type))
+	return append(b, a.Hash[:]...)
+}
+
+func (a *EncodedAddress) String() string {
+	s, err := bech32Encode(AddressHRP, a.Bytes())
+	if err != nil {
+		return ""
+	}
+	return s
+}
+
+// IsZero reports whether the address is the zero address of its network.
+func (a *EncodedAddress) IsZero() bool {
+	return a.Type == config.QBIT_ADDRESS_PREFIX && a.Hash == [AddressHashLen]byte{}
+}
+
+func (a *EncodedAddress) Equal(b *EncodedAddress) bool {
+	return bytes.Equal(a.Bytes(), b.Bytes())
+}
+
+// ParseAddress decodes and validates an address string.
+func ParseAddress(s string) (*EncodedAddress, error) {
+	hrp, data, err := bech32Decode(s)
+	if err != nil {
+		return nil, err
+	}
+	if hrp != AddressHRP {
+		return nil, ErrAddressHRP
+	}
+	if len(data) != encodedAddressLen {
+		return nil, errBech32Length
+	}
+	if data[0] != AddressVersion {
+		return nil, ErrAddressVersion
+	}
+	a := &EncodedAddress{Version: data[0], Type: uint32(data[3])}
+	copy(a.Network[:], data[1:3])
+	copy(a.Hash[:], data[4:])
+	if !isKnownNetwork(a.Network) {
+		return nil, ErrAddressNetwork
+	}
+	if !isKnownAddressType(a.Type) {
+		return nil, ErrAddressType
+	}
+	return a, nil
+}
+
+// ZeroAddressOf returns the zero (burn) address of a network.
+func ZeroAddressOf(network config.NetworkID) string {
+	a, err := NewEncodedAddress(network, config.QBIT_ADDRESS_PREFIX, make([]byte, AddressHashLen))
+	if err != nil {
+		return ""
+	}
+	return a.String()
+}
@@ -0,0 +1,110 @@
+package address
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/quantosnetwork/Quantos/sdk/config"
+)
+
+func testAddress(t *testing.T, network config.NetworkID, addrType uint32) *EncodedAddress {
+	t.Helper()
+	hash := make([]byte, AddressHashLen)
+	for i := range hash {
+		hash[i] = byte(i*7 + int(addrType))
+	}
+	a, err := NewEncodedAddress(network, addrType, hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func TestAddressRoundTrip(t *testing.T) {
+	networks := []config.NetworkID{config.LIVENET, config.TESTNET, config.LOCALNET}
+	types := []uint32{config.QBIT_ADDRESS_PREFIX, config.TX_ADDRESS_PREFIX, config.BLOCK_ADDRESS_PREFIX, config.CONTRACT_ADDRESS_PREFIX}
+	for _, n := range networks {
+		for _, typ := range types {
+			a := testAddress(t, n, typ)
+			s := a.String()
+			if !strings.HasPrefix(s, AddressHRP+"1") {
+				t.Fatalf("missing hrp: %s", s)
+			}
+			b, err := ParseAddress(s)
+			if err != nil {
+				t.Fatalf("%s: %v", s, err)
+			}
+			if !a.Equal(b) || b.Network != n || b.Type != typ {
+				t.Fatalf("round trip mismatch for %s", s)
+			}
+			if _, err := ParseAddress(strings.ToUpper(s)); err != nil {
+				t.Fatalf("upper case address rejected: %v", err)
+			}
+		}
+	}
+}
+
+func TestAddressTypoRejected(t *testing.T) {
+	s := testAddress(t, config.LIVENET, config.QBIT_ADDRESS_PREFIX).String()
+	for i := len(AddressHRP) + 1; i < len(s); i++ {
+		for _, c := range bech32Charset {
+			if byte(c) == s[i] {
+				continue
+			}
+			typo := s[:i] + string(c) + s[i+1:]
+			if _, err := ParseAddress(typo); err == nil {
+				t.Fatalf("typo at %d accepted: %s", i, typo)
+			}
+		}
+	}
+}
+
+func TestAddressInvalid(t *testing.T) {
+	valid := testAddress(t, config.TESTNET, config.TX_ADDRESS_PREFIX)
+	s := valid.String()
+
+	mixed := strings.ToUpper(s[:10]) + s[10:]
+	if _, err := ParseAddress(mixed); err != errBech32Case {
+		t.Fatalf("mixed case: got %v", err)
+	}
+
+	other, _ := bech32Encode("qbtc", valid.Bytes())
+	if _, err := ParseAddress(other); err != ErrAddressHRP {
+		t.Fatalf("wrong hrp: got %v", err)
+	}
+
+	raw := valid.Bytes()
+	raw[0] = AddressVersion + 1
+	s, _ = bech32Encode(AddressHRP, raw)
+	if _, err := ParseAddress(s); err != ErrAddressVersion {
+		t.Fatalf("wrong version: got %v", err)
+	}
+
+	raw = valid.Bytes()
+	raw[1], raw[2] = 0xde, 0xad
+	s, _ = bech32Encode(AddressHRP, raw)
+	if _, err := ParseAddress(s); err != ErrAddressNetwork {
+		t.Fatalf("unknown network: got %v", err)
+	}
+
+	raw = valid.Bytes()
+	raw[3] = 0x7f
+	s, _ = bech32Encode(AddressHRP, raw)
+	if _, err := ParseAddress(s); err != ErrAddressType {
+		t.Fatalf("unknown type: got %v", err)
+	}
+
+	if _, err := ParseAddress(s[:len(s)-1]); err == nil {
+		t.Fatal("truncated address accepted")
+	}
+}
+
+func TestZeroAddress(t *testing.T) {
+	if ZeroAddressOf(config.LIVENET) != config.ZEROADDRESS {
+		t.Fatalf("config.ZEROADDRESS is not the live network zero address: %s", ZeroAddressOf(config.LIVENET))
+	}
+	a, err := ParseAddress(ZeroAddressOf(config.TESTNET))
+	if err != nil || !a.IsZero() || a.Network != config.TESTNET {
+		t.Fatalf("bad testnet zero address: %v", err)
+	}
+}
@@ -0,0 +1,270 @@
+package address
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"sync"
+
+	"github.com/google/uuid"
+	"go.uber.org/atomic"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20poly1305"
+	"lukechampine.com/frand"
+)
+
+/*
+
+	Keystore files
+
+	Wallet secrets are stored on disk as versioned JSON:
+
+	{
+	  "version": 1,
+	  "id": "<uuid>",
+	  "address": "<address the secret controls>",
+	  "crypto": {
+	    "cipher": "xchacha20-poly1305",
+	    "ciphertext": "<hex>",
+	    "nonce": "<hex, 24 bytes>",
+	    "kdf": "argon2id",
+	    "kdfparams": {"salt": "<hex>", "time": 3, "memory": 65536, "threads": 4, "keylen": 32}
+	  }
+	}
+
+	The encryption key is derived from the passphrase with argon2id. The
+	version, id and address are authenticated as additional data so they
+	cannot be swapped between files.
+
+*/
+
+const (
+	KeystoreVersion = 1
+
+	keystoreCipher = "xchacha20-poly1305"
+	keystoreKDF    = "argon2id"
+	keystoreSalt   = 32
+
+	// bounds on the kdf parameters read from keystore files, so a crafted
+	// file can not make unlocking run for days or exhaust memory
+	maxKDFTime   = 16
+	maxKDFMemory = 1024 * 1024 // KiB, 1 GiB
+)
+
+var (
+	ErrKeystoreLocked     = errors.New("quantos keystore: keystore is locked")
+	ErrKeystorePassphrase = errors.New("quantos keystore: wrong passphrase or corrupted file")
+)
+
+// DefaultKDFParams are the argon2id parameters used for new keystore files.
+var DefaultKDFParams = KDFParams{
+	Time:    3,
+	Memory:  64 * 1024,
+	Threads: 4,
+	KeyLen:  chacha20poly1305.KeySize,
+}
+
+type KDFParams struct {
+	Salt    string `json:"salt"`
+	Time    uint32 `json:"time"`
+	Memory  uint32 `json:"memory"`
+	Threads uint8  `json:"threads"`
+	KeyLen  uint32 `json:"keylen"`
+}
+
+type KeyFileCrypto struct {
+	Cipher     string    `json:"cipher"`
+	CipherText string    `json:"ciphertext"`
+	Nonce      string    `json:"nonce"`
+	KDF        string    `json:"kdf"`
+	KDFParams  KDFParams `json:"kdfparams"`
+}
+
+// KeyFile is the on-disk representation of an encrypted wallet secret.
+type KeyFile struct {
+	Version int           `json:"version"`
+	ID      uuid.UUID     `json:"id"`
+	Address string        `json:"address"`
+	Crypto  KeyFileCrypto `json:"crypto"`
+}
+
+func (k *KeyFile) additionalData() []byte {
+	ad, _ := json.Marshal([]interface{}{k.Version, k.ID, k.Address})
+	return ad
+}
+
+func (p KDFParams) deriveKey(passphrase string) ([]byte, error) {
+	salt, err := hex.DecodeString(p.Salt)
+	if err != nil || len(salt) == 0 {
+		return nil, errors.New("quantos keystore: invalid kdf salt")
+	}
+	if p.Time == 0 || p.Time > maxKDFTime || p.Memory == 0 || p.Memory > maxKDFMemory ||
+		p.Threads == 0 || p.KeyLen != chacha20poly1305.KeySize {
+		return nil, errors.New("quantos keystore: invalid kdf parameters")
+	}
+	return argon2.IDKey([]byte(passphrase), salt, p.Time, p.Memory, p.Threads, p.KeyLen), nil
+}
+
+// EncryptKey seals secret with passphrase using the default KDF parameters.
+func EncryptKey(secret []byte, address string, passphrase string) (*KeyFile, error) {
+	return EncryptKeyWithParams(secret, address, passphrase, DefaultKDFParams)
+}
+
+func EncryptKeyWithParams(secret []byte, address string, passphrase string, params KDFParams) (*KeyFile, error) {
+	params.Salt = hex.EncodeToString(frand.Bytes(keystoreSalt))
+	key, err := params.deriveKey(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := frand.Bytes(aead.NonceSize())
+	k := &KeyFile{
+		Version: KeystoreVersion,
+		ID:      uuid.New(),
+		Address: address,
+	}
+	ct := aead.Seal(nil, nonce, secret, k.additionalData())
+	k.Crypto = KeyFileCrypto{
+		Cipher:     keystoreCipher,
+		CipherText: hex.EncodeToString(ct),
+		Nonce:      hex.EncodeToString(nonce),
+		KDF:        keystoreKDF,
+		KDFParams:  params,
+	}
+	return k, nil
+}
+
+// Decrypt returns the secret sealed in the key file.
+func (k *KeyFile) Decrypt(passphrase string) ([]byte, error) {
+	if k.Version != KeystoreVersion {
+		return nil, errors.New("quantos keystore: unsupported version")
+	}
+	if k.Crypto.Cipher != keystoreCipher || k.Crypto.KDF != keystoreKDF {
+		return nil, errors.New("quantos keystore: unsupported cipher or kdf")
+	}
+	key, err := k.Crypto.KDFParams.deriveKey(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := hex.DecodeString(k.Crypto.Nonce)
+	if err != nil || len(nonce) != aead.NonceSize() {
+		return nil, errors.New("quantos keystore: invalid nonce")
+	}
+	ct, err := hex.DecodeString(k.Crypto.CipherText)
+	if err != nil {
+		return nil, errors.New("quantos keystore: invalid ciphertext")
+	}
+	secret, err := aead.Open(nil, nonce, ct, k.additionalData())
+	if err != nil {
+		return nil, ErrKeystorePassphrase
+	}
+	return secret, nil
+}
+
+func (k *KeyFile) ToJSON() ([]byte, error) {
+	return json.MarshalIndent(k, "", "  ")
+}
+
+func KeyFileFromJSON(b []byte) (*KeyFile, error) {
+	k := &KeyFile{}
+	if err := json.Unmarshal(b, k); err != nil {
+		return nil, err
+	}
+	if k.Version != KeystoreVersion {
+		return nil, errors.New("quantos keystore: unsupported version")
+	}
+	return k, nil
+}
+
+// SaveKeyFile writes the key file readable by its owner only.
+func SaveKeyFile(path string, k *KeyFile) error {
+	b, err := k.ToJSON()
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, b, 0600)
+}
+
+func LoadKeyFile(path string) (*KeyFile, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return KeyFileFromJSON(b)
+}
+
+// Keystore holds a key file and, while unlocked, its decrypted secret.
+// Lock follows Account.Lock: it is true whenever the secret is not in
+// memory.
+type Keystore struct {
+	KeyFile *KeyFile
+	Lock    atomic.Bool
+	mu      sync.Mutex
+	secret  []byte
+}
+
+// NewKeystore returns a locked keystore for the key file.
+func NewKeystore(k *KeyFile) *Keystore {
+	ks := &Keystore{KeyFile: k}
+	ks.Lock.Store(true)
+	return ks
+}
+
+func OpenKeystore(path string) (*Keystore, error) {
+	k, err := LoadKeyFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewKeystore(k), nil
+}
+
+// Unlock decrypts the secret and keeps it in memory until Relock.
+func (ks *Keystore) Unlock(passphrase string) error {
+	secret, err := ks.KeyFile.Decrypt(passphrase)
+	if err != nil {
+		return err
+	}
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	ks.wipe()
+	ks.secret = secret
+	ks.Lock.Store(false)
+	return nil
+}
+
+// Relock wipes the decrypted secret from memory.
+func (ks *Keystore) Relock() {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	ks.wipe()
+	ks.Lock.Store(true)
+}
+
+func (ks *Keystore) wipe() {
+	for i := range ks.secret {
+		ks.secret[i] = 0
+	}
+	ks.secret = nil
+}
+
+// Secret returns a copy of the decrypted secret.
+func (ks *Keystore) Secret() ([]byte, error) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	if ks.Lock.Load() {
+		return nil, ErrKeystoreLocked
+	}
+	return append([]byte(nil), ks.secret...), nil
+}
+
+func (ks *Keystore) Address() string {
+	return ks.KeyFile.Address
+}
@@ -0,0 +1,74 @@
+package address
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+)
+
+var testKDFParams = KDFParams{Time: 1, Memory: 1024, Threads: 1, KeyLen: 32}
+
+func TestKeystoreRoundTrip(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	k, err := EncryptKeyWithParams(secret, "0xaddress", "correct horse", testKDFParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "key.json")
+	if err := SaveKeyFile(path, k); err != nil {
+		t.Fatal(err)
+	}
+
+	ks, err := OpenKeystore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ks.Lock.Load() {
+		t.Fatal("a new keystore must be locked")
+	}
+	if _, err := ks.Secret(); err != ErrKeystoreLocked {
+		t.Fatalf("expected ErrKeystoreLocked, got %v", err)
+	}
+	if err := ks.Unlock("wrong horse"); err != ErrKeystorePassphrase {
+		t.Fatalf("expected ErrKeystorePassphrase, got %v", err)
+	}
+	if err := ks.Unlock("correct horse"); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ks.Secret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, secret) {
+		t.Fatal("decrypted secret differs")
+	}
+	ks.Relock()
+	if _, err := ks.Secret(); err != ErrKeystoreLocked {
+		t.Fatal("relocked keystore still returns its secret")
+	}
+
+	// the address is authenticated, moving the ciphertext to another one fails
+	k.Address = "0xanother"
+	if _, err := k.Decrypt("correct horse"); err == nil {
+		t.Fatal("decrypted a key file with a tampered address")
+	}
+}
+
+func TestKeystoreKDFBounds(t *testing.T) {
+	k, err := EncryptKeyWithParams([]byte("secret"), "0xaddress", "pass", testKDFParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a crafted file asking for 4 TiB or 2^32 passes is refused up front
+	for _, p := range []KDFParams{
+		{Time: 1, Memory: 1<<32 - 1, Threads: 1, KeyLen: 32},
+		{Time: 1<<32 - 1, Memory: 1024, Threads: 1, KeyLen: 32},
+	} {
+		p.Salt = k.Crypto.KDFParams.Salt
+		crafted := *k
+		crafted.Crypto.KDFParams = p
+		if _, err := crafted.Decrypt("pass"); err == nil || err == ErrKeystorePassphrase {
+			t.Fatalf("kdf parameters %+v accepted: %v", p, err)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package address
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"sort"
+
+	"github.com/quantosnetwork/Quantos/crypto"
+	"github.com/quantosnetwork/Quantos/sdk/config"
+	"github.com/zeebo/blake3"
+	"go.dedis.ch/kyber/v3"
+)
+
+/*
+
+	Multisig (m-of-n) accounts
+
+	A policy is a signer set and a threshold. Encoded as
+
+	[4]byte  version, incremented by each key rotation
+	[1]byte  threshold m
+	[1]byte  number of signers n
+	n*[32]byte signer public keys, sorted
+
+	The account address is derived from the version 0 policy and never
+	changes, rotations only replace the policy stored on chain for it.
+
+*/
+
+const MaxMultisigSigners = 16
+
+const multisigKeyLen = 32
+
+var (
+	ErrMultisigThreshold    = errors.New("quantos multisig: threshold must be between 1 and the number of signers")
+	ErrMultisigSigners      = errors.New("quantos multisig: invalid signer set")
+	ErrMultisigDuplicate    = errors.New("quantos multisig: duplicate signer")
+	ErrMultisigUnknown      = errors.New("quantos multisig: signature from a key outside the signer set")
+	ErrMultisigBadSignature = errors.New("quantos multisig: invalid signature")
+	ErrMultisigNotEnough    = errors.New("quantos multisig: not enough signatures")
+)
+
+type MultisigPolicy struct {
+	Version   uint32
+	Threshold int
+	Signers   [][]byte
+}
+
+//go:generate go run github.com/quantosnetwork/Quantos/encoder/quantosgen -type SignerSignature
+
+// SignerSignature is the signature of one signer of a multisig account.
+type SignerSignature struct {
+	PubKey    []byte `quantos:"pk"`
+	Signature []byte `quantos:"sig"`
+}
+
+// NewMultisigPolicy returns the version 0 policy of a new m-of-n account.
+func NewMultisigPolicy(threshold int, signers []kyber.Point) (*MultisigPolicy, error) {
+	return newMultisigPolicy(0, threshold, signers)
+}
+
+func newMultisigPolicy(version uint32, threshold int, signers []kyber.Point) (*MultisigPolicy, error) {
+	keys := make([][]byte, 0, len(signers))
+	for _, s := range signers {
+		b, err := s.MarshalBinary()
+		if err != nil || len(b) != multisigKeyLen {
+			return nil, ErrMultisigSigners
+		}
+		keys = append(keys, b)
+	}
+	sort.Slice(keys, func(i, j int) bool { return bytes.Compare(keys[i], keys[j]) < 0 })
+	p := &MultisigPolicy{Version: version, Threshold: threshold, Signers: keys}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Validate checks the threshold and that the signers are distinct, sorted
+// public keys.
+func (p *MultisigPolicy) Validate() error {
+	n := len(p.Signers)
+	if n == 0 || n > MaxMultisigSigners {
+		return ErrMultisigSigners
+	}
+	if p.Threshold < 1 || p.Threshold > n {
+		return ErrMultisigThreshold
+	}
+	for i, k := range p.Signers {
+		if _, err := crypto.PublicKeyFromBytes(k); err != nil || len(k) != multisigKeyLen {
+			return ErrMultisigSigners
+		}
+		if i > 0 {
+			switch bytes.Compare(p.Signers[i-1], k) {
+			case 0:
+				return ErrMultisigDuplicate
+			case 1:
+				return ErrMultisigSigners
+			}
+		}
+	}
+	return nil
+}
+
+// Rotate returns the next version of the policy with a new signer set.
+func (p *MultisigPolicy) Rotate(threshold int, signers []kyber.Point) (*MultisigPolicy, error) {
+	return newMultisigPolicy(p.Version+1, threshold, signers)
+}
+
+func (p *MultisigPolicy) Bytes() []byte {
+	b := make([]byte, 6, 6+len(p.Signers)*multisigKeyLen)
+	binary.BigEndian.PutUint32(b, p.Version)
+	b[4] = byte(p.Threshold)
+	b[5] = byte(len(p.Signers))
+	for _, k := range p.Signers {
+		b = append(b, k...)
+	}
+	return b
+}
+
+func ParseMultisigPolicy(b []byte) (*MultisigPolicy, error) {
+	if len(b) < 6 || len(b) != 6+int(b[5])*multisigKeyLen {
+		return nil, ErrMultisigSigners
+	}
+	p := &MultisigPolicy{
+		Version:   binary.BigEndian.Uint32(b),
+		Threshold: int(b[4]),
+	}
+	for i := 6; i < len(b); i += multisigKeyLen {
+		p.Signers = append(p.Signers, append([]byte(nil), b[i:i+multisigKeyLen]...))
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Address returns the account address of a version 0 policy.
+func (p *MultisigPolicy) Address(network config.NetworkID) (*EncodedAddress, error) {
+	if p.Version != 0 {
+		return nil, errors.New("quantos multisig: the address is derived from the version 0 policy")
+	}
+	h := make([]byte, AddressHashLen)
+	blake3.DeriveKey("qbit-multisig-address", p.Bytes(), h)
+	return NewEncodedAddress(network, config.QBIT_ADDRESS_PREFIX, h)
+}
+
+func (p *MultisigPolicy) isSigner(pk []byte) bool {
+	for _, k := range p.Signers {
+		if bytes.Equal(k, pk) {
+			return true
+		}
+	}
+	return false
+}
+
+// Verify checks that at least Threshold distinct signers of the policy
+// signed msg. Any signature from outside the set or that does not verify
+// fails the whole set.
+func (p *MultisigPolicy) Verify(msg []byte, sigs []SignerSignature) error {
+	seen := make(map[string]bool, len(sigs))
+	for _, s := range sigs {
+		if !p.isSigner(s.PubKey) {
+			return ErrMultisigUnknown
+		}
+		if seen[string(s.PubKey)] {
+			return ErrMultisigDuplicate
+		}
+		seen[string(s.PubKey)] = true
+		pub, err := crypto.PublicKeyFromBytes(s.PubKey)
+		if err != nil || !crypto.VerifySchnorr(pub, msg, s.Signature) {
+			return ErrMultisigBadSignature
+		}
+	}
+	if len(seen) < p.Threshold {
+		return ErrMultisigNotEnough
+	}
+	return nil
+}
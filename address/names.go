@@ -0,0 +1,44 @@
+package address
+
+import (
+	"errors"
+	"strings"
+)
+
+// NameSuffix ends every name of the name service, e.g. alice.qbit.
+const NameSuffix = ".qbit"
+
+const (
+	minNameLabel = 3
+	maxNameLabel = 63
+)
+
+var ErrInvalidName = errors.New("quantos names: invalid name")
+
+// IsName reports whether s looks like a name rather than an address.
+func IsName(s string) bool {
+	return strings.HasSuffix(strings.ToLower(s), NameSuffix)
+}
+
+// NormalizeName lower cases a name and checks it: one label of 3 to 63
+// characters from a-z, 0-9 and inner hyphens, followed by NameSuffix.
+func NormalizeName(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !strings.HasSuffix(name, NameSuffix) {
+		return "", ErrInvalidName
+	}
+	label := strings.TrimSuffix(name, NameSuffix)
+	if len(label) < minNameLabel || len(label) > maxNameLabel {
+		return "", ErrInvalidName
+	}
+	if label[0] == '-' || label[len(label)-1] == '-' {
+		return "", ErrInvalidName
+	}
+	for i := 0; i < len(label); i++ {
+		c := label[i]
+		if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-') {
+			return "", ErrInvalidName
+		}
+	}
+	return name, nil
+}
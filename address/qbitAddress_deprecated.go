@@ -2,14 +2,11 @@ package address
 
 import (
 	"bytes"
-	"encoding/base32"
 	"encoding/binary"
 	"encoding/json"
-	"github.com/davecgh/go-spew/spew"
-	"github.com/iancoleman/strcase"
+	"github.com/quantosnetwork/Quantos/sdk/config"
 	"github.com/zeebo/blake3"
 	"lukechampine.com/frand"
-	"strings"
 	"unsafe"
 )
 
@@ -18,20 +15,11 @@ import (
 	QBIT Addresses
 
 	Purpose: Wallet addresses
-	Size: 36 bytes
-	Prefix: 0x (0x = byte representation)
 
-	Structure of Address
-
-	[All Qbit address starts with]
-    [4]byte  uint32 [ 0x38A ]
-	[4]byte crc32 IEEE of kyber public key generated at the same time as the p2p peer ID
-	[20]byte of cryptographically safe random bytes data
-	[8]byte as a random nonce to avoid addresses collisions
-
-	0x is then appended to the address to mark it as bytes
-
-	@Suggestion we could but qbit: [address here] to differentiate from other 0x address types
+	A QBITAddress is built from a 64 bytes seed (random or BIP39). Its
+	string form is the versioned bech32m encoding described in encoding.go:
+	format version, network id, address type and the 32 bytes hash of the
+	seed, protected by a checksum.
 
 	906 / 38A = cointype
 
@@ -69,22 +57,22 @@ func (q *QBITAddress) Hash() []byte {
 
 }
 
-func (q *QBITAddress) String() string {
-	h := q.Hash()
-	str := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(h)
-	str = Reverse(str)
-	str = strings.ToLower(str)
-	str = strcase.ToCamel(str)
-	str = Add0xPrefix(str)
-	return str
+// Encoded returns the structured form of the address.
+func (q *QBITAddress) Encoded() (*EncodedAddress, error) {
+	return NewEncodedAddress(config.NetworkID(q.network), q.prefix, q.Hash())
 }
 
-func QBITAddressFromAddressString(str string) string {
-	strsplit := strings.Split(str, "0x")
-	revAddrStr := strsplit[0]
-	addrStr := Reverse(revAddrStr)
-	return addrStr
+func (q *QBITAddress) String() string {
+	a, err := q.Encoded()
+	if err != nil {
+		return ""
+	}
+	return a.String()
+}
 
+// QBITAddressFromAddressString parses and validates an address string.
+func QBITAddressFromAddressString(str string) (*EncodedAddress, error) {
+	return ParseAddress(str)
 }
 
 func Add0xPrefix(addr string) string {
@@ -110,29 +98,27 @@ func NewQBITAddress(networkID [2]byte, version [2]byte, prefix uint32, context u
 	return q
 }
 
-func getZeroAddress(netID [2]byte, version [2]byte, prefix uint32, context uint32) *QBITAddress {
-	q := GenerateNewQbitAddress(netID, version, prefix, context)
-
-	buf := make([]byte, 64)
-	b := fillBufferWithZeros(buf)
-	spew.Dump(b[:])
-	q.seed = b[:]
-	q.context = context
-
+// QBITAddressFromSeed rebuilds an address from a known seed, e.g. one read
+// back from a keystore file.
+func QBITAddressFromSeed(networkID [2]byte, version [2]byte, prefix uint32, context uint32, seed []byte) *QBITAddress {
+	q := &QBITAddress{
+		network:         networkID,
+		protocolVersion: version,
+		prefix:          prefix,
+		context:         context,
+		seed:            append([]byte(nil), seed...),
+		words:           new([16]uint32),
+	}
 	return q
-
 }
 
-func ZeroAddress(netID [2]byte, version [2]byte, prefix uint32, context uint32) string {
-	addr := getZeroAddress(netID, version, prefix, context)
-	return addr.String()
+// Seed returns the secret seed the address is built from.
+func (q *QBITAddress) Seed() []byte {
+	return q.seed
 }
 
-func fillBufferWithZeros(buf []byte) []byte {
-	for i, _ := range buf {
-		buf[i] = 0
-	}
-	return buf
+func ZeroAddress(netID [2]byte, version [2]byte, prefix uint32, context uint32) string {
+	return ZeroAddressOf(config.NetworkID(netID))
 }
 
 func generateRandomBytes() [32]byte {
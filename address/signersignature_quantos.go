@@ -0,0 +1,58 @@
+// Code generated by quantosgen. DO NOT EDIT.
+
+package address
+
+import (
+	"github.com/quantosnetwork/Quantos/decoder"
+	"github.com/quantosnetwork/Quantos/encoder"
+)
+
+// MarshalQuantos encodes x like encoder.Marshal.
+func (x *SignerSignature) MarshalQuantos(e *encoder.Encoder) error {
+	if x == nil {
+		return encoder.NilError(x)
+	}
+	e.BeginDict()
+	if x.PubKey != nil {
+		e.EncodeString("pk")
+		e.EncodeBytes(x.PubKey)
+	}
+	if x.Signature != nil {
+		e.EncodeString("sig")
+		e.EncodeBytes(x.Signature)
+	}
+	e.End()
+	return nil
+}
+
+// UnmarshalQuantos decodes x like decoder.Unmarshal.
+func (x *SignerSignature) UnmarshalQuantos(d *decoder.Decoder) error {
+	if err := d.OpenDict(); err != nil {
+		return err
+	}
+	for d.More() {
+		key, err := d.DecodeBytes()
+		if err != nil {
+			return err
+		}
+		switch string(key) {
+		case "pk":
+			b1, err := d.DecodeBytes()
+			if err != nil {
+				return err
+			}
+			x.PubKey = append([]byte(nil), b1...)
+		case "sig":
+			b2, err := d.DecodeBytes()
+			if err != nil {
+				return err
+			}
+			x.Signature = append([]byte(nil), b2...)
+		default:
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		}
+	}
+	return d.Close()
+}
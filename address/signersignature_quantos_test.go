@@ -0,0 +1,173 @@
+// Code generated by quantosgen. DO NOT EDIT.
+
+package address
+
+import (
+	"bytes"
+	"math/big"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/quantosnetwork/Quantos/decoder"
+	"github.com/quantosnetwork/Quantos/encoder"
+)
+
+type quantosReflectSignerSignature SignerSignature
+
+func TestQuantosSignerSignature(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 500; i++ {
+		in := new(SignerSignature)
+		quantosRandom(r, reflect.ValueOf(in).Elem(), 0)
+
+		want, wantErr := encoder.Marshal((*quantosReflectSignerSignature)(in))
+		var e encoder.Encoder
+		err := in.MarshalQuantos(&e)
+		if (err == nil) != (wantErr == nil) {
+			t.Fatalf("generated error %v, reflective error %v", err, wantErr)
+		}
+		if err != nil {
+			continue
+		}
+		got := e.Bytes()
+		if !bytes.Equal(got, want) {
+			t.Fatalf("encodings differ:\n%q\n%q", got, want)
+		}
+
+		out, ref := new(SignerSignature), new(SignerSignature)
+		if err := decoder.Unmarshal(got, out); err != nil {
+			t.Fatal(err)
+		}
+		if err := decoder.Unmarshal(got, (*quantosReflectSignerSignature)(ref)); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(out, ref) {
+			t.Fatalf("decodings differ:\n%+v\n%+v", out, ref)
+		}
+		again, err := encoder.Marshal(out)
+		if err != nil || !bytes.Equal(again, got) {
+			t.Fatalf("round trip differs: %v\n%q\n%q", err, again, got)
+		}
+	}
+}
+
+func quantosSampleSignerSignature(b *testing.B) (*SignerSignature, []byte) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		in := new(SignerSignature)
+		quantosRandom(r, reflect.ValueOf(in).Elem(), 0)
+		if data, err := encoder.Marshal(in); err == nil {
+			return in, data
+		}
+	}
+	b.Fatal("no encodable sample")
+	return nil, nil
+}
+
+func BenchmarkQuantosSignerSignatureMarshal(b *testing.B) {
+	in, _ := quantosSampleSignerSignature(b)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var e encoder.Encoder
+		if err := in.MarshalQuantos(&e); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkQuantosSignerSignatureMarshalReflect(b *testing.B) {
+	in, _ := quantosSampleSignerSignature(b)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := encoder.Marshal((*quantosReflectSignerSignature)(in)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkQuantosSignerSignatureUnmarshal(b *testing.B) {
+	_, data := quantosSampleSignerSignature(b)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := decoder.Unmarshal(data, new(SignerSignature)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkQuantosSignerSignatureUnmarshalReflect(b *testing.B) {
+	_, data := quantosSampleSignerSignature(b)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := decoder.Unmarshal(data, (*quantosReflectSignerSignature)(new(SignerSignature))); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func quantosRandom(r *rand.Rand, v reflect.Value, depth int) {
+	if v.Type() == reflect.TypeOf(big.Int{}) {
+		b := make([]byte, r.Intn(80))
+		r.Read(b)
+		n := new(big.Int).SetBytes(b)
+		if r.Intn(2) == 0 {
+			n.Neg(n)
+		}
+		v.Set(reflect.ValueOf(n).Elem())
+		return
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		v.SetBool(r.Intn(2) == 1)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n := r.Int63() >> uint(r.Intn(64))
+		if r.Intn(2) == 0 {
+			n = -n
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		v.SetUint(r.Uint64() >> uint(r.Intn(64)))
+	case reflect.String:
+		b := make([]byte, r.Intn(8))
+		r.Read(b)
+		v.SetString(string(b))
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			quantosRandom(r, v.Index(i), depth)
+		}
+	case reflect.Slice:
+		if depth > 3 || r.Intn(4) == 0 {
+			return
+		}
+		n := 1 + r.Intn(3)
+		v.Set(reflect.MakeSlice(v.Type(), n, n))
+		for i := 0; i < n; i++ {
+			quantosRandom(r, v.Index(i), depth+1)
+		}
+	case reflect.Map:
+		if depth > 3 || r.Intn(4) == 0 {
+			return
+		}
+		v.Set(reflect.MakeMap(v.Type()))
+		for i := 1 + r.Intn(3); i > 0; i-- {
+			k := reflect.New(v.Type().Key()).Elem()
+			e := reflect.New(v.Type().Elem()).Elem()
+			quantosRandom(r, k, depth+1)
+			quantosRandom(r, e, depth+1)
+			v.SetMapIndex(k, e)
+		}
+	case reflect.Ptr:
+		if depth > 3 || r.Intn(4) == 0 {
+			return
+		}
+		v.Set(reflect.New(v.Type().Elem()))
+		quantosRandom(r, v.Elem(), depth+1)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath == "" {
+				quantosRandom(r, v.Field(i), depth)
+			}
+		}
+	}
+}
@@ -2,8 +2,12 @@
 package cmd
 
 import (
+	"encoding/hex"
 	"fmt"
 	"github.com/quantosnetwork/Quantos/address"
+	"github.com/quantosnetwork/Quantos/sdk"
+	"github.com/quantosnetwork/Quantos/sdk/config"
+	"lukechampine.com/frand"
 
 	"github.com/spf13/cobra"
 )
@@ -18,35 +22,161 @@ var addressCmd = &cobra.Command{
 	},
 }
 
-//var bip39 bool
-
 var newAddressCmd = &cobra.Command{
 	Use:   "new-address",
 	Short: "create a new wallet address",
-	Long:  "create a new wallet address",
-	Run: func(cmd *cobra.Command, args []string) {
+	Long:  "create a new wallet address, encrypted into a keystore file when --pass is set",
+	RunE: func(cmd *cobra.Command, args []string) error {
+
+		bip39, _ := cmd.Flags().GetBool("bip39")
+		mnemonic, _ := cmd.Flags().GetString("mnemonic")
+		mnemonicPass, _ := cmd.Flags().GetString("mnemonic-pass")
+		words, _ := cmd.Flags().GetInt("words")
+		language, _ := cmd.Flags().GetString("language")
+		pass, _ := cmd.Flags().GetString("pass")
+		out, _ := cmd.Flags().GetString("out")
+		network, _ := cmd.Flags().GetString("network")
 
-		//	bip39 := true
+		netID, err := networkID(network)
+		if err != nil {
+			return err
+		}
+		if bip39 && mnemonic == "" {
+			m, err := address.NewMnemonic(words, address.Language(language))
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Your recovery words (write them down): %s\n", m)
+			mnemonic = m
+		}
 
-		compress, _ := cmd.Flags().GetBool("compress")
+		var a *address.Account
+		var seed []byte
+		if mnemonic != "" {
+			a, seed, err = address.AccountFromMnemonic(netID, mnemonic, mnemonicPass)
+		} else {
+			seed = frand.Bytes(64)
+			a, err = address.NewAccount(netID, seed)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Your wallet address: %s\n", a.Address)
+		if pass == "" {
+			fmt.Println("no --pass given, the wallet was not saved")
+			return nil
+		}
+		return writeKeyFile(seed, a.Address, pass, out)
+	},
+}
+
+var exportAddressCmd = &cobra.Command{
+	Use:   "export",
+	Short: "decrypt a keystore file and print its wallet seed",
+	Long:  "decrypt a keystore file and print its wallet seed",
+	RunE: func(cmd *cobra.Command, args []string) error {
 		pass, _ := cmd.Flags().GetString("pass")
-		mnemonic, _ := cmd.Flags().GetString("mnemonic")
-		address.NewQBITAddress(compress, pass, mnemonic)
-		//address.NewAddress(compress, pass, mnemonic)
+		keyfile, _ := cmd.Flags().GetString("keyfile")
 
+		ks, err := address.OpenKeystore(keyfile)
+		if err != nil {
+			return err
+		}
+		if err := ks.Unlock(pass); err != nil {
+			return err
+		}
+		defer ks.Relock()
+		seed, err := ks.Secret()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Address: %s\nSeed: %s\n", ks.Address(), hex.EncodeToString(seed))
+		return nil
 	},
 }
 
+var importAddressCmd = &cobra.Command{
+	Use:   "import",
+	Short: "import a wallet seed into an encrypted keystore file",
+	Long:  "import a wallet seed into an encrypted keystore file",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pass, _ := cmd.Flags().GetString("pass")
+		out, _ := cmd.Flags().GetString("out")
+		network, _ := cmd.Flags().GetString("network")
+		seedHex, _ := cmd.Flags().GetString("seed")
+
+		netID, err := networkID(network)
+		if err != nil {
+			return err
+		}
+		if pass == "" {
+			return fmt.Errorf("a passphrase is required to import a wallet")
+		}
+		seed, err := hex.DecodeString(seedHex)
+		if err != nil || len(seed) == 0 {
+			return fmt.Errorf("invalid seed: %q", seedHex)
+		}
+		a, err := address.NewAccount(netID, seed)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Your wallet address: %s\n", a.Address)
+		return writeKeyFile(seed, a.Address, pass, out)
+	},
+}
+
+func networkID(name string) (config.NetworkID, error) {
+	switch name {
+	case "live", "test", "local":
+	default:
+		return config.NetworkID{}, fmt.Errorf("unknown network %q, expected live, test or local", name)
+	}
+	sdk.GetAddressSDK().InitSDK(name)
+	return sdk.CurrentNetworkID, nil
+}
+
+func writeKeyFile(seed []byte, addr string, pass string, out string) error {
+	k, err := address.EncryptKey(seed, addr, pass)
+	if err != nil {
+		return err
+	}
+	if out == "" {
+		out = k.ID.String() + ".json"
+	}
+	if err := address.SaveKeyFile(out, k); err != nil {
+		return err
+	}
+	fmt.Printf("Keystore saved to %s\n", out)
+	return nil
+}
+
 func init() {
 	rootCmd.AddCommand(addressCmd)
 
+	addressCmd.PersistentFlags().String("network", "live", "network of the address (live, test or local)")
+
 	newAddressCmd.Flags().BoolP("bip39", "b", false, "mnemonic code for generating deterministic keys")
 	newAddressCmd.Flags().BoolP("compress", "c", true, "generate a compressed public key")
-	newAddressCmd.Flags().String("pass", "", "protect bip39 address with passphrase")
+	newAddressCmd.Flags().String("pass", "", "encrypt the new wallet into a keystore file with this passphrase")
 	newAddressCmd.Flags().Int("number", 10, "set number of keys to generate")
 	newAddressCmd.Flags().String("mnemonic", "", "optional list of words to re-generate a root key")
+	newAddressCmd.Flags().String("mnemonic-pass", "", "optional bip39 passphrase protecting the mnemonic")
+	newAddressCmd.Flags().Int("words", 24, "number of bip39 words to generate (12, 15, 18, 21 or 24)")
+	newAddressCmd.Flags().String("language", string(address.English), "language of the generated bip39 words")
+	newAddressCmd.Flags().String("out", "", "keystore file to write (default <keystore id>.json)")
+
+	exportAddressCmd.Flags().String("keyfile", "", "keystore file to decrypt")
+	exportAddressCmd.Flags().String("pass", "", "passphrase of the keystore file")
+	_ = exportAddressCmd.MarkFlagRequired("keyfile")
+
+	importAddressCmd.Flags().String("seed", "", "hex encoded wallet seed")
+	importAddressCmd.Flags().String("pass", "", "passphrase to encrypt the keystore file with")
+	importAddressCmd.Flags().String("out", "", "keystore file to write (default <keystore id>.json)")
+	_ = importAddressCmd.MarkFlagRequired("seed")
 
 	addressCmd.AddCommand(newAddressCmd)
+	addressCmd.AddCommand(exportAddressCmd)
+	addressCmd.AddCommand(importAddressCmd)
 
 	// Here you will define your flags and configuration settings.
 
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/quantosnetwork/Quantos/protocol"
+	"github.com/spf13/cobra"
+)
+
+var dumpCmd = &cobra.Command{
+	Use:   "dump [file]",
+	Short: "print an encoded protocol payload as JSON",
+	Long: `print an encoded protocol payload, read from file or stdin, as annotated JSON.
+With --type the payload is validated against that message of the schema and printed
+with its field types, the built-in schema has the Block, Tx and Vote messages.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		isHex, _ := cmd.Flags().GetBool("hex")
+		msg, _ := cmd.Flags().GetString("type")
+		schemaFile, _ := cmd.Flags().GetString("schema")
+
+		var (
+			data []byte
+			err  error
+		)
+		if len(args) == 1 {
+			data, err = os.ReadFile(args[0])
+		} else {
+			data, err = io.ReadAll(os.Stdin)
+		}
+		if err != nil {
+			return err
+		}
+		if isHex {
+			if data, err = hex.DecodeString(string(bytes.TrimSpace(data))); err != nil {
+				return fmt.Errorf("invalid hex input: %w", err)
+			}
+		}
+
+		if msg == "" {
+			out, err := protocol.Dump(data)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(out))
+			return nil
+		}
+		schema := protocol.Messages
+		if schemaFile != "" {
+			src, err := os.ReadFile(schemaFile)
+			if err != nil {
+				return err
+			}
+			if schema, err = protocol.ParseSchema(string(src)); err != nil {
+				return err
+			}
+		}
+		if invalid := schema.Validate(msg, data); invalid != nil {
+			// still show what is there
+			if out, err := protocol.Dump(data); err == nil {
+				fmt.Println(string(out))
+			}
+			return invalid
+		}
+		out, err := schema.Dump(msg, data)
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(dumpCmd)
+
+	dumpCmd.Flags().Bool("hex", false, "the input is hex encoded")
+	dumpCmd.Flags().String("type", "", "message type to validate and print the payload as")
+	dumpCmd.Flags().String("schema", "", "schema file declaring the message types (default the built-in messages)")
+}
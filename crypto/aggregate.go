@@ -0,0 +1,141 @@
+package crypto
+
+import (
+	"errors"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/pairing/bn256"
+	"go.dedis.ch/kyber/v3/sign"
+	"go.dedis.ch/kyber/v3/sign/bdn"
+)
+
+/*
+
+	Validator vote aggregation
+
+	Validators sign block commits with BLS keys on the bn256 pairing curve
+	(signatures on G1, public keys on G2). Votes are aggregated with the
+	BDN scheme, which weights each signature and key by a coefficient
+	derived from the whole validator set so a rogue validator cannot forge
+	an aggregate by choosing its key after seeing the others.
+
+	A block commit carries a single AggregateSignature:
+
+	[]byte Signature  the aggregated G1 point
+	[]byte Bitmap     bit i set when validator i of the set signed
+
+*/
+
+var voteSuite = bn256.NewSuite()
+
+// VoteSuite returns the pairing suite used for validator votes.
+func VoteSuite() pairing.Suite {
+	return voteSuite
+}
+
+type ValidatorKeys struct {
+	PubKey  kyber.Point
+	PrivKey kyber.Scalar
+}
+
+func GenerateValidatorKeys() *ValidatorKeys {
+	sk, pk := bdn.NewKeyPair(voteSuite, voteSuite.RandomStream())
+	return &ValidatorKeys{PubKey: pk, PrivKey: sk}
+}
+
+// SignVote signs a block commit message.
+func (v *ValidatorKeys) SignVote(msg []byte) ([]byte, error) {
+	return bdn.Sign(voteSuite, v.PrivKey, msg)
+}
+
+// VerifyVote verifies a single, non aggregated, validator vote.
+func VerifyVote(pub kyber.Point, msg, sig []byte) bool {
+	return bdn.Verify(voteSuite, pub, msg, sig) == nil
+}
+
+type AggregateSignature struct {
+	Signature []byte
+	Bitmap    []byte
+}
+
+// AggregateVotes folds the votes of a validator set into one signature.
+// votes maps the index of a validator in validators to its signature.
+func AggregateVotes(validators []kyber.Point, votes map[int][]byte) (*AggregateSignature, error) {
+	mask, err := sign.NewMask(voteSuite, validators, nil)
+	if err != nil {
+		return nil, err
+	}
+	for i := range votes {
+		if err := mask.SetBit(i, true); err != nil {
+			return nil, err
+		}
+	}
+	// signatures must be in the order of the enabled bits
+	sigs := make([][]byte, 0, len(votes))
+	for i := range validators {
+		if sig, ok := votes[i]; ok {
+			sigs = append(sigs, sig)
+		}
+	}
+	agg, err := bdn.AggregateSignatures(voteSuite, sigs, mask)
+	if err != nil {
+		return nil, err
+	}
+	b, err := agg.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return &AggregateSignature{Signature: b, Bitmap: mask.Mask()}, nil
+}
+
+// Signers returns the indexes of the validators that signed.
+func (a *AggregateSignature) Signers() []int {
+	var signers []int
+	for i := 0; i < len(a.Bitmap)*8; i++ {
+		if a.Bitmap[i>>3]&(1<<uint(i&7)) != 0 {
+			signers = append(signers, i)
+		}
+	}
+	return signers
+}
+
+// Verify checks the aggregated signature of msg against the validator set
+// and returns how many validators signed.
+func (a *AggregateSignature) Verify(validators []kyber.Point, msg []byte) (int, error) {
+	mask, err := sign.NewMask(voteSuite, validators, nil)
+	if err != nil {
+		return 0, err
+	}
+	if err := mask.SetMask(a.Bitmap); err != nil {
+		return 0, err
+	}
+	// bits past the end of the validator set must not be set
+	for _, i := range a.Signers() {
+		if i >= len(validators) {
+			return 0, errors.New("aggregate signature: signer bitmap exceeds the validator set")
+		}
+	}
+	signers := mask.CountEnabled()
+	if signers == 0 {
+		return 0, errors.New("aggregate signature: no signers")
+	}
+	pub, err := bdn.AggregatePublicKeys(voteSuite, mask)
+	if err != nil {
+		return 0, err
+	}
+	if err := bdn.Verify(voteSuite, pub, msg, a.Signature); err != nil {
+		return 0, err
+	}
+	return signers, nil
+}
+
+// VerifyQuorum verifies the aggregated signature and checks that at least
+// threshold validators signed.
+func (a *AggregateSignature) VerifyQuorum(validators []kyber.Point, msg []byte, threshold int) bool {
+	signers, err := a.Verify(validators, msg)
+	if err != nil {
+		return false
+	}
+	return signers >= threshold
+}
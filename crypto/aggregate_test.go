@@ -0,0 +1,125 @@
+package crypto
+
+import (
+	"testing"
+
+	"go.dedis.ch/kyber/v3"
+)
+
+func generateValidatorSet(n int) ([]*ValidatorKeys, []kyber.Point) {
+	keys := make([]*ValidatorKeys, n)
+	pubs := make([]kyber.Point, n)
+	for i := range keys {
+		keys[i] = GenerateValidatorKeys()
+		pubs[i] = keys[i].PubKey
+	}
+	return keys, pubs
+}
+
+func signVotes(keys []*ValidatorKeys, msg []byte, skip func(int) bool) map[int][]byte {
+	votes := make(map[int][]byte)
+	for i, k := range keys {
+		if skip != nil && skip(i) {
+			continue
+		}
+		sig, err := k.SignVote(msg)
+		if err != nil {
+			panic(err)
+		}
+		votes[i] = sig
+	}
+	return votes
+}
+
+func TestAggregateVotes(t *testing.T) {
+	msg := []byte("block commit")
+	keys, pubs := generateValidatorSet(10)
+	votes := signVotes(keys, msg, func(i int) bool { return i%3 == 0 })
+
+	agg, err := AggregateVotes(pubs, votes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signers, err := agg.Verify(pubs, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signers != len(votes) {
+		t.Fatalf("got %d signers, want %d", signers, len(votes))
+	}
+	if got := agg.Signers(); len(got) != len(votes) || got[0] != 1 {
+		t.Fatalf("unexpected signer indexes %v", got)
+	}
+	if !agg.VerifyQuorum(pubs, msg, 6) || agg.VerifyQuorum(pubs, msg, 7) {
+		t.Fatal("quorum check is wrong")
+	}
+	if _, err := agg.Verify(pubs, []byte("another block")); err == nil {
+		t.Fatal("aggregate verified for the wrong message")
+	}
+
+	// claiming a validator signed when it did not must fail
+	agg.Bitmap[0] |= 1
+	if _, err := agg.Verify(pubs, msg); err == nil {
+		t.Fatal("aggregate verified with a forged bitmap")
+	}
+}
+
+func benchmarkVotes(n int) ([]*ValidatorKeys, []kyber.Point, map[int][]byte, []byte) {
+	msg := []byte("block commit")
+	keys, pubs := generateValidatorSet(n)
+	return keys, pubs, signVotes(keys, msg, nil), msg
+}
+
+func BenchmarkVerifyVotesIndividually(b *testing.B) {
+	_, pubs, votes, msg := benchmarkVotes(100)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j, pub := range pubs {
+			if !VerifyVote(pub, msg, votes[j]) {
+				b.Fatal("invalid vote")
+			}
+		}
+	}
+}
+
+func BenchmarkVerifySchnorrIndividually(b *testing.B) {
+	msg := []byte("block commit")
+	keys := make([]*HardenedKeys, 100)
+	sigs := make([][]byte, len(keys))
+	for i := range keys {
+		keys[i] = GenerateHardenedKeys()
+		sigs[i] = keys[i].Sign(msg)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j, k := range keys {
+			if !k.VerifySignature(msg, sigs[j]) {
+				b.Fatal("invalid signature")
+			}
+		}
+	}
+}
+
+func BenchmarkAggregateVotes(b *testing.B) {
+	_, pubs, votes, _ := benchmarkVotes(100)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := AggregateVotes(pubs, votes); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkVerifyAggregate(b *testing.B) {
+	_, pubs, votes, msg := benchmarkVotes(100)
+	agg, err := AggregateVotes(pubs, votes)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := agg.Verify(pubs, msg); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
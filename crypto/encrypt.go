@@ -0,0 +1,150 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+
+	"github.com/zeebo/blake3"
+	"go.dedis.ch/kyber/v3"
+	"golang.org/x/crypto/chacha20poly1305"
+	"lukechampine.com/frand"
+)
+
+/*
+
+	Public key encryption to a HardenedKeys public key
+
+	KEM:  ephemeral edwards25519 key e, E = e*G, shared point S = e*PK
+	KDF:  key = blake3 derive key "quantos-encrypt", E || PK || S
+	AEAD: XChaCha20-Poly1305 with a random nonce, the header and the
+	      caller's additional data are authenticated
+
+	[1]byte  version (1)
+	[32]byte E
+	[24]byte nonce
+	...      ciphertext and tag
+
+	The recipient recomputes S = sk*E. A fresh ephemeral key per message
+	keeps messages to the same recipient unlinkable.
+
+*/
+
+const (
+	encryptVersion   = 1
+	encryptPointLen  = 32
+	encryptHeaderLen = 1 + encryptPointLen + chacha20poly1305.NonceSizeX
+)
+
+var (
+	ErrDecrypt       = errors.New("quantos crypto: message authentication failed")
+	ErrCiphertext    = errors.New("quantos crypto: malformed ciphertext")
+	ErrWeakPublicKey = errors.New("quantos crypto: peer public key is not in the prime order group")
+)
+
+// SharedPoint is the Diffie-Hellman point of a private key and a peer
+// public key, as in GenerateAndVerifySharedKeys. Peer keys must be in the
+// prime order subgroup: a low order key makes the point predictable and a
+// mixed order one lets a decryption oracle leak sk mod 8.
+func SharedPoint(sk kyber.Scalar, peer kyber.Point) (kyber.Point, error) {
+	if peer.Equal(hdSuite.Point().Null()) || !primeOrder(peer) {
+		return nil, ErrWeakPublicKey
+	}
+	return hdSuite.Point().Mul(sk, peer), nil
+}
+
+// primeOrder reports whether l*p is the identity, l the group order. The
+// scalar l-1 is -1, so l*p is (l-1)*p + p.
+func primeOrder(p kyber.Point) bool {
+	minusOne := hdSuite.Scalar().Neg(hdSuite.Scalar().One())
+	lp := hdSuite.Point().Add(hdSuite.Point().Mul(minusOne, p), p)
+	return lp.Equal(hdSuite.Point().Null())
+}
+
+// DeriveSharedKey derives a 32 bytes symmetric key both sides of a key exchange
+// compute from their private key and the other's public key.
+func DeriveSharedKey(own *HardenedKeys, peer kyber.Point) ([]byte, error) {
+	s, err := SharedPoint(own.PrivKey, peer)
+	if err != nil {
+		return nil, err
+	}
+	a, _ := own.PubKey.MarshalBinary()
+	b, _ := peer.MarshalBinary()
+	if bytes.Compare(a, b) > 0 {
+		a, b = b, a
+	}
+	sb, _ := s.MarshalBinary()
+	return deriveKey("quantos-shared-key", a, b, sb), nil
+}
+
+func deriveKey(context string, parts ...[]byte) []byte {
+	h := blake3.NewDeriveKey(context)
+	var l [4]byte
+	for _, p := range parts {
+		binary.BigEndian.PutUint32(l[:], uint32(len(p)))
+		h.Write(l[:])
+		h.Write(p)
+	}
+	return h.Sum(nil)[:chacha20poly1305.KeySize]
+}
+
+// Encrypt seals plaintext for the owner of recipient. aad is authenticated
+// but not encrypted and must be given again to Decrypt.
+func Encrypt(recipient kyber.Point, plaintext, aad []byte) ([]byte, error) {
+	e := hdSuite.Scalar().Pick(hdSuite.RandomStream())
+	s, err := SharedPoint(e, recipient)
+	if err != nil {
+		return nil, err
+	}
+	ephemeral, _ := hdSuite.Point().Mul(e, nil).MarshalBinary()
+	aead, err := encryptAEAD(ephemeral, recipient, s)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, 0, encryptHeaderLen+len(plaintext)+aead.Overhead())
+	out = append(out, encryptVersion)
+	out = append(out, ephemeral...)
+	out = append(out, frand.Bytes(aead.NonceSize())...)
+	return aead.Seal(out, out[1+encryptPointLen:], plaintext, encryptAD(out, aad)), nil
+}
+
+// Decrypt opens a message sealed by Encrypt to h.PubKey.
+func (h *HardenedKeys) Decrypt(ciphertext, aad []byte) ([]byte, error) {
+	if len(ciphertext) < encryptHeaderLen+chacha20poly1305.Overhead || ciphertext[0] != encryptVersion {
+		return nil, ErrCiphertext
+	}
+	header := ciphertext[:encryptHeaderLen]
+	ephemeral := hdSuite.Point()
+	if err := ephemeral.UnmarshalBinary(header[1 : 1+encryptPointLen]); err != nil {
+		return nil, ErrCiphertext
+	}
+	s, err := SharedPoint(h.PrivKey, ephemeral)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := encryptAEAD(header[1:1+encryptPointLen], h.PubKey, s)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := aead.Open(nil, header[1+encryptPointLen:], ciphertext[encryptHeaderLen:], encryptAD(header, aad))
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plaintext, nil
+}
+
+func encryptAEAD(ephemeral []byte, recipient, shared kyber.Point) (cipher.AEAD, error) {
+	pk, err := recipient.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	sb, _ := shared.MarshalBinary()
+	return chacha20poly1305.NewX(deriveKey("quantos-encrypt", ephemeral, pk, sb))
+}
+
+func encryptAD(header, aad []byte) []byte {
+	ad := make([]byte, 0, encryptHeaderLen+len(aad))
+	ad = append(ad, header[:encryptHeaderLen]...)
+	return append(ad, aad...)
+}
@@ -0,0 +1,139 @@
+package crypto
+
+import (
+	"bytes"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"strings"
+
+	"github.com/zeebo/blake3"
+)
+
+/*
+
+	Extended key serialisation
+
+	[1]byte  format version (1)
+	[2]byte  network id
+	[1]byte  depth
+	[4]byte  parent fingerprint
+	[4]byte  child index
+	[32]byte chain code
+	[32]byte public key (qpub) or private scalar (qprv)
+	[4]byte  checksum, first 4 bytes of blake3 of the prefix and
+	         everything above
+
+	The bytes are base32 encoded (lower case, no padding) after a "qpub" or
+	"qprv" prefix. The checksum covers the prefix so a qpub can not be
+	passed off as a qprv, or the other way round.
+
+	A qpub lets a watch-only service derive the non-hardened children of
+	the key, i.e. receive addresses, without any private key.
+
+*/
+
+const (
+	ExtendedPublicPrefix  = "qpub"
+	ExtendedPrivatePrefix = "qprv"
+
+	extendedKeyVersion = 1
+	extendedKeyLen     = 1 + 2 + 1 + 4 + 4 + hdChainLength + 32
+	extendedKeyCsum    = 4
+)
+
+var (
+	ErrInvalidExtendedKey  = errors.New("quantos hd: invalid extended key")
+	ErrExtendedKeyChecksum = errors.New("quantos hd: extended key checksum mismatch")
+)
+
+var extKeyEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// Serialize encodes the key. Private keys are encoded as qprv, call Neuter
+// first to share a watch-only qpub.
+func (k *ExtendedKey) Serialize() (string, error) {
+	var key []byte
+	var err error
+	prefix := ExtendedPublicPrefix
+	if k.IsPrivate() {
+		prefix = ExtendedPrivatePrefix
+		key, err = k.PrivKey.MarshalBinary()
+	} else {
+		key, err = k.PubKey.MarshalBinary()
+	}
+	if err != nil {
+		return "", err
+	}
+	buf := make([]byte, 0, extendedKeyLen+extendedKeyCsum)
+	buf = append(buf, extendedKeyVersion)
+	buf = append(buf, k.Network[:]...)
+	buf = append(buf, k.Depth)
+	buf = append(buf, k.ParentFingerprint[:]...)
+	var index [4]byte
+	binary.BigEndian.PutUint32(index[:], k.Index)
+	buf = append(buf, index[:]...)
+	buf = append(buf, k.ChainCode...)
+	buf = append(buf, key...)
+	if len(buf) != extendedKeyLen {
+		return "", ErrInvalidExtendedKey
+	}
+	buf = append(buf, extKeyChecksum(prefix, buf)...)
+	return prefix + strings.ToLower(extKeyEncoding.EncodeToString(buf)), nil
+}
+
+func (k *ExtendedKey) String() string {
+	s, _ := k.Serialize()
+	return s
+}
+
+// ParseExtendedKey decodes a qpub or qprv string.
+func ParseExtendedKey(s string) (*ExtendedKey, error) {
+	var prefix string
+	switch {
+	case strings.HasPrefix(s, ExtendedPublicPrefix):
+		prefix = ExtendedPublicPrefix
+	case strings.HasPrefix(s, ExtendedPrivatePrefix):
+		prefix = ExtendedPrivatePrefix
+	default:
+		return nil, ErrInvalidExtendedKey
+	}
+	private := prefix == ExtendedPrivatePrefix
+	buf, err := extKeyEncoding.DecodeString(strings.ToUpper(s[len(ExtendedPublicPrefix):]))
+	if err != nil || len(buf) != extendedKeyLen+extendedKeyCsum {
+		return nil, ErrInvalidExtendedKey
+	}
+	payload, csum := buf[:extendedKeyLen], buf[extendedKeyLen:]
+	if !bytes.Equal(extKeyChecksum(prefix, payload), csum) {
+		return nil, ErrExtendedKeyChecksum
+	}
+	if payload[0] != extendedKeyVersion {
+		return nil, errors.New("quantos hd: unsupported extended key version")
+	}
+	k := &ExtendedKey{}
+	copy(k.Network[:], payload[1:3])
+	k.Depth = payload[3]
+	copy(k.ParentFingerprint[:], payload[4:8])
+	k.Index = binary.BigEndian.Uint32(payload[8:12])
+	k.ChainCode = append([]byte(nil), payload[12:12+hdChainLength]...)
+	key := payload[12+hdChainLength:]
+	if private {
+		k.PrivKey = hdSuite.Scalar()
+		if err := k.PrivKey.UnmarshalBinary(key); err != nil {
+			return nil, ErrInvalidExtendedKey
+		}
+		k.PubKey = hdSuite.Point().Mul(k.PrivKey, nil)
+	} else {
+		k.PubKey = hdSuite.Point()
+		if err := k.PubKey.UnmarshalBinary(key); err != nil {
+			return nil, ErrInvalidExtendedKey
+		}
+	}
+	return k, nil
+}
+
+func extKeyChecksum(prefix string, payload []byte) []byte {
+	h := blake3.New()
+	h.Write([]byte(prefix))
+	h.Write(payload)
+	return h.Sum(nil)[:extendedKeyCsum]
+}
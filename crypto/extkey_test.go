@@ -0,0 +1,70 @@
+package crypto
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestExtendedKeySerialization(t *testing.T) {
+	m, err := NewMasterExtendedKey(bytes.Repeat([]byte{0x07}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Network = [2]byte{0x0a, 0x00}
+	path, _ := ParseDerivationPath("m/44'/906'/3'")
+	account, _ := m.DerivePath(path)
+
+	xprv, err := account.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	xpub, err := account.Neuter().Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if xprv[:4] != ExtendedPrivatePrefix || xpub[:4] != ExtendedPublicPrefix {
+		t.Fatalf("unexpected prefixes: %s %s", xprv[:4], xpub[:4])
+	}
+
+	pub, err := ParseExtendedKey(xpub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pub.IsPrivate() || pub.Network != account.Network || pub.Depth != 3 ||
+		pub.Index != account.Index || pub.ParentFingerprint != account.ParentFingerprint ||
+		!pub.PubKey.Equal(account.PubKey) {
+		t.Fatal("qpub did not round trip")
+	}
+	priv, err := ParseExtendedKey(xprv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !priv.PrivKey.Equal(account.PrivKey) || !priv.PubKey.Equal(account.PubKey) {
+		t.Fatal("qprv did not round trip")
+	}
+
+	// the watch-only key derives the same receive addresses
+	a, _ := priv.DerivePath([]uint32{0, 9})
+	b, _ := pub.DerivePath([]uint32{0, 9})
+	if !a.PubKey.Equal(b.PubKey) {
+		t.Fatal("qpub derived a different child")
+	}
+
+	typo := []byte(xpub)
+	if typo[20] == 'a' {
+		typo[20] = 'b'
+	} else {
+		typo[20] = 'a'
+	}
+	if _, err := ParseExtendedKey(string(typo)); err != ErrExtendedKeyChecksum {
+		t.Fatalf("expected ErrExtendedKeyChecksum, got %v", err)
+	}
+
+	// the checksum covers the prefix, a qpub can not pass for a qprv
+	swapped := []string{ExtendedPrivatePrefix + xpub[4:], ExtendedPublicPrefix + xprv[4:]}
+	for _, s := range swapped {
+		if _, err := ParseExtendedKey(s); err != ErrExtendedKeyChecksum {
+			t.Fatalf("swapped prefix: expected ErrExtendedKeyChecksum, got %v", err)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package crypto
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/zeebo/blake3"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/group/edwards25519"
+)
+
+/*
+
+	Hierarchical deterministic keys
+
+	BIP32 style derivation adapted to the edwards25519 keys of HardenedKeys.
+	Keys are tweaked additively so public parents can derive non-hardened
+	public children:
+
+	master:
+	  sk = HMAC-SHA512("Quantos seed", 0x00 || seed) mod l
+	  c  = HMAC-SHA512("Quantos seed", 0x01 || seed)[0:32]
+
+	child i of (sk, pk, c):
+	  data = 0x00 || ser(sk) || ser32(i)   if i >= 2^31 (hardened)
+	  data = ser(pk) || ser32(i)           otherwise
+	  t     = HMAC-SHA512(c, 0x00 || data) mod l
+	  c'    = HMAC-SHA512(c, 0x01 || data)[0:32]
+	  sk'   = sk + t
+	  pk'   = pk + t*G
+
+	Scalars are taken from the full 64 bytes of HMAC output so they are
+	uniform modulo l.
+
+*/
+
+const (
+	// HardenedKeyStart is the first hardened child index (i').
+	HardenedKeyStart uint32 = 0x80000000
+
+	MinSeedBytes = 16
+	MaxSeedBytes = 64
+
+	hdMasterKey   = "Quantos seed"
+	hdChainLength = 32
+)
+
+var (
+	ErrDeriveHardenedFromPublic = errors.New("quantos hd: cannot derive a hardened key from a public key")
+	ErrInvalidDerivationPath    = errors.New("quantos hd: invalid derivation path")
+	ErrInvalidSeedLength        = errors.New("quantos hd: seed must be 16 to 64 bytes")
+)
+
+var hdSuite = edwards25519.NewBlakeSHA256Ed25519()
+
+// ExtendedKey is a key of the HD tree together with its chain code. PrivKey
+// is nil for public (watch-only) keys. Network is carried along for
+// serialisation and does not change the derivation.
+type ExtendedKey struct {
+	Network           [2]byte
+	PrivKey           kyber.Scalar
+	PubKey            kyber.Point
+	ChainCode         []byte
+	Depth             uint8
+	ParentFingerprint [4]byte
+	Index             uint32
+}
+
+func hdHMAC(key []byte, domain byte, data ...[]byte) []byte {
+	mac := hmac.New(sha512.New, key)
+	mac.Write([]byte{domain})
+	for _, d := range data {
+		mac.Write(d)
+	}
+	return mac.Sum(nil)
+}
+
+// NewMasterExtendedKey derives the root of the HD tree from a seed, e.g.
+// the 64 bytes BIP39 seed.
+func NewMasterExtendedKey(seed []byte) (*ExtendedKey, error) {
+	if len(seed) < MinSeedBytes || len(seed) > MaxSeedBytes {
+		return nil, ErrInvalidSeedLength
+	}
+	sk := hdSuite.Scalar().SetBytes(hdHMAC([]byte(hdMasterKey), 0x00, seed))
+	return &ExtendedKey{
+		PrivKey:   sk,
+		PubKey:    hdSuite.Point().Mul(sk, nil),
+		ChainCode: hdHMAC([]byte(hdMasterKey), 0x01, seed)[:hdChainLength],
+	}, nil
+}
+
+func (k *ExtendedKey) IsPrivate() bool {
+	return k.PrivKey != nil
+}
+
+// Fingerprint identifies the key as the parent of its children.
+func (k *ExtendedKey) Fingerprint() [4]byte {
+	var fp [4]byte
+	pub, _ := k.PubKey.MarshalBinary()
+	h := blake3.Sum256(pub)
+	copy(fp[:], h[:4])
+	return fp
+}
+
+// Child derives the child key at index i. Indexes from HardenedKeyStart up
+// are hardened and need a private key.
+func (k *ExtendedKey) Child(i uint32) (*ExtendedKey, error) {
+	var index [4]byte
+	binary.BigEndian.PutUint32(index[:], i)
+
+	var data []byte
+	if i >= HardenedKeyStart {
+		if !k.IsPrivate() {
+			return nil, ErrDeriveHardenedFromPublic
+		}
+		sk, err := k.PrivKey.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		data = append([]byte{0x00}, sk...)
+	} else {
+		pub, err := k.PubKey.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		data = pub
+	}
+	data = append(data, index[:]...)
+
+	tweak := hdSuite.Scalar().SetBytes(hdHMAC(k.ChainCode, 0x00, data))
+	child := &ExtendedKey{
+		Network:           k.Network,
+		PubKey:            hdSuite.Point().Add(k.PubKey, hdSuite.Point().Mul(tweak, nil)),
+		ChainCode:         hdHMAC(k.ChainCode, 0x01, data)[:hdChainLength],
+		Depth:             k.Depth + 1,
+		ParentFingerprint: k.Fingerprint(),
+		Index:             i,
+	}
+	if k.IsPrivate() {
+		child.PrivKey = hdSuite.Scalar().Add(k.PrivKey, tweak)
+	}
+	return child, nil
+}
+
+// DerivePath walks down the tree following path.
+func (k *ExtendedKey) DerivePath(path []uint32) (*ExtendedKey, error) {
+	key := k
+	for _, i := range path {
+		var err error
+		key, err = key.Child(i)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return key, nil
+}
+
+// Neuter returns the public (watch-only) version of the key.
+func (k *ExtendedKey) Neuter() *ExtendedKey {
+	n := *k
+	n.PrivKey = nil
+	n.ChainCode = append([]byte(nil), k.ChainCode...)
+	return &n
+}
+
+// HardenedKeys returns the key pair of a private extended key, for signing.
+func (k *ExtendedKey) HardenedKeys() (*HardenedKeys, error) {
+	if !k.IsPrivate() {
+		return nil, errors.New("quantos hd: public extended key has no private key")
+	}
+	return &HardenedKeys{
+		Group:   hdSuite,
+		PubKey:  k.PubKey.Clone(),
+		PrivKey: k.PrivKey.Clone(),
+		Suite:   hdSuite,
+	}, nil
+}
+
+// ParseDerivationPath parses paths like m/44'/906'/0'/0/5. Hardened indexes
+// are marked with ' or h.
+func ParseDerivationPath(path string) ([]uint32, error) {
+	parts := strings.Split(strings.TrimSpace(path), "/")
+	if len(parts) == 0 || parts[0] != "m" {
+		return nil, ErrInvalidDerivationPath
+	}
+	indexes := make([]uint32, 0, len(parts)-1)
+	for _, p := range parts[1:] {
+		hardened := strings.HasSuffix(p, "'") || strings.HasSuffix(p, "h")
+		if hardened {
+			p = p[:len(p)-1]
+		}
+		i, err := strconv.ParseUint(p, 10, 32)
+		if err != nil || uint32(i) >= HardenedKeyStart {
+			return nil, ErrInvalidDerivationPath
+		}
+		if hardened {
+			i += uint64(HardenedKeyStart)
+		}
+		indexes = append(indexes, uint32(i))
+	}
+	return indexes, nil
+}
+
+// FormatDerivationPath is the inverse of ParseDerivationPath.
+func FormatDerivationPath(path []uint32) string {
+	var sb strings.Builder
+	sb.WriteString("m")
+	for _, i := range path {
+		sb.WriteString("/")
+		if i >= HardenedKeyStart {
+			sb.WriteString(strconv.FormatUint(uint64(i-HardenedKeyStart), 10))
+			sb.WriteString("'")
+		} else {
+			sb.WriteString(strconv.FormatUint(uint64(i), 10))
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,81 @@
+package crypto
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestExtendedKeyDerivation(t *testing.T) {
+	seed := bytes.Repeat([]byte{0x42}, 64)
+	m1, err := NewMasterExtendedKey(seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m2, _ := NewMasterExtendedKey(seed)
+	path, err := ParseDerivationPath("m/44'/906'/0'/0/5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := m1.DerivePath(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := m2.DerivePath(path)
+	if !a.PubKey.Equal(b.PubKey) || !a.PrivKey.Equal(b.PrivKey) {
+		t.Fatal("derivation is not deterministic")
+	}
+	if a.Depth != 5 || a.Index != 5 {
+		t.Fatalf("unexpected depth %d or index %d", a.Depth, a.Index)
+	}
+
+	// the private key matches the public key
+	k, err := a.HardenedKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := []byte("derived")
+	if !k.VerifySignature(msg, k.Sign(msg)) {
+		t.Fatal("derived key pair does not sign")
+	}
+
+	// non-hardened children derived from the public parent are the same
+	account, _ := m1.DerivePath(path[:3])
+	priv, _ := account.DerivePath(path[3:])
+	pub, err := account.Neuter().DerivePath(path[3:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !priv.PubKey.Equal(pub.PubKey) || !bytes.Equal(priv.ChainCode, pub.ChainCode) {
+		t.Fatal("public derivation differs from private derivation")
+	}
+	if _, err := account.Neuter().Child(HardenedKeyStart); err != ErrDeriveHardenedFromPublic {
+		t.Fatalf("expected ErrDeriveHardenedFromPublic, got %v", err)
+	}
+
+	other, _ := NewMasterExtendedKey(bytes.Repeat([]byte{0x43}, 64))
+	c, _ := other.DerivePath(path)
+	if c.PubKey.Equal(a.PubKey) {
+		t.Fatal("different seeds derived the same key")
+	}
+}
+
+func TestParseDerivationPath(t *testing.T) {
+	p, err := ParseDerivationPath("m/44'/906h/0'/1/5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []uint32{44 + HardenedKeyStart, 906 + HardenedKeyStart, HardenedKeyStart, 1, 5}
+	for i := range want {
+		if p[i] != want[i] {
+			t.Fatalf("got %v, want %v", p, want)
+		}
+	}
+	if s := FormatDerivationPath(p); s != "m/44'/906'/0'/1/5" {
+		t.Fatalf("got %s", s)
+	}
+	for _, bad := range []string{"", "44'/0", "m/x", "m/2147483648", "m//1"} {
+		if _, err := ParseDerivationPath(bad); err == nil {
+			t.Fatalf("parsed invalid path %q", bad)
+		}
+	}
+}
@@ -1,32 +1,68 @@
 package crypto
 
-
-import  (
-oqs "github.com/open-quantum-safe/liboqs-go/oqs"
+import (
+	"fmt"
 	"log"
 )
 
+// KEMNAME is the key encapsulation mechanism used between quantos peers.
+const KEMNAME = "kyber512"
+
+// KemDetails mirrors the algorithm details exposed by liboqs so that both
+// KeyEncapsulation backends report the same sizes for the same algorithm.
+type KemDetails struct {
+	ClaimedNISTLevel   int
+	IsINDCCA           bool
+	LengthCiphertext   int
+	LengthPublicKey    int
+	LengthSecretKey    int
+	LengthSharedSecret int
+	Name               string
+	Version            string
+}
+
+func (d KemDetails) String() string {
+	return fmt.Sprintf("Name: %s\nVersion: %s\nClaimed NIST level: %d\n"+
+		"Is IND_CCA: %v\nLength public key (bytes): %d\nLength secret key ("+
+		"bytes): %d\nLength ciphertext (bytes): %d\nLength shared secret ("+
+		"bytes): %d", d.Name, d.Version, d.ClaimedNISTLevel, d.IsINDCCA,
+		d.LengthPublicKey, d.LengthSecretKey, d.LengthCiphertext,
+		d.LengthSharedSecret)
+}
+
+// KeyEncapsulation is the post-quantum KEM used for the peer key exchange.
+//
+// By default it is backed by a pure Go Kyber implementation. Building with
+// the liboqs tag switches to the cgo liboqs-go binding instead. Both
+// backends produce byte-for-byte identical keys, ciphertexts and shared
+// secrets so nodes built either way can talk to each other.
+type KeyEncapsulation interface {
+	Init(algName string, secretKey []byte) error
+	Details() KemDetails
+	GenerateKeyPair() ([]byte, error)
+	ExportSecretKey() []byte
+	EncapSecret(publicKey []byte) (ciphertext, sharedSecret []byte, err error)
+	DecapSecret(ciphertext []byte) ([]byte, error)
+	Clean()
+}
+
 type KemKeys struct {
-	Client oqs.KeyEncapsulation
-	PubKey []byte
+	Client       KeyEncapsulation
+	PubKey       []byte
 	SharedSecret chan []byte
 }
 
 type ServerKeys struct {
-	Server oqs.KeyEncapsulation
-	ciphertext []byte
+	Server       KeyEncapsulation
+	ciphertext   []byte
 	SharedSecret []byte
 	ClientPubKey chan []byte
 }
 
-
-
 func NewKemClient() *KemKeys {
 	k := &KemKeys{}
-	client := oqs.KeyEncapsulation{}
-	k.Client = client
-	defer k.Client.Clean()
-	if err := k.Client.Init("kyber512", nil); err != nil {
+	k.Client = NewKeyEncapsulation()
+	if err := k.Client.Init(KEMNAME, nil); err != nil {
 		panic(err)
 	}
 
@@ -43,12 +79,10 @@ func NewKemClient() *KemKeys {
 
 func NewKemServer() *ServerKeys {
 	k := &ServerKeys{}
-	k.Server = oqs.KeyEncapsulation{}
-	defer k.Server.Clean()
-	if err := k.Server.Init("kyber512", nil); err != nil {
+	k.Server = NewKeyEncapsulation()
+	if err := k.Server.Init(KEMNAME, nil); err != nil {
 		log.Fatal(err)
 	}
 	k.ClientPubKey = make(chan []byte)
 	return k
 }
-
@@ -0,0 +1,133 @@
+//go:build !liboqs
+// +build !liboqs
+
+package crypto
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/sha256"
+	"fmt"
+	"testing"
+)
+
+// katDRBG is the AES-256 CTR DRBG from NIST's PQCgenKAT randombytes.c.
+type katDRBG struct {
+	key [32]byte
+	v   [16]byte
+}
+
+func newKatDRBG(seed *[48]byte) *katDRBG {
+	g := &katDRBG{}
+	g.update(seed)
+	return g
+}
+
+func (g *katDRBG) incV() {
+	for j := 15; j >= 0; j-- {
+		if g.v[j] == 255 {
+			g.v[j] = 0
+		} else {
+			g.v[j]++
+			break
+		}
+	}
+}
+
+func (g *katDRBG) update(pd *[48]byte) {
+	var buf [48]byte
+	b, _ := aes.NewCipher(g.key[:])
+	for i := 0; i < 3; i++ {
+		g.incV()
+		b.Encrypt(buf[i*16:(i+1)*16], g.v[:])
+	}
+	if pd != nil {
+		for i := range buf {
+			buf[i] ^= pd[i]
+		}
+	}
+	copy(g.key[:], buf[:32])
+	copy(g.v[:], buf[32:])
+}
+
+func (g *katDRBG) fill(x []byte) {
+	var block [16]byte
+	b, _ := aes.NewCipher(g.key[:])
+	for len(x) > 0 {
+		g.incV()
+		b.Encrypt(block[:], g.v[:])
+		n := copy(x, block[:])
+		x = x[n:]
+	}
+	g.update(nil)
+}
+
+// TestKemKnownAnswers regenerates the PQCkemKAT .rsp file of the Kyber
+// reference implementation (the same vectors liboqs is tested against) and
+// compares its SHA-256 with the digest of the official file.
+func TestKemKnownAnswers(t *testing.T) {
+	kats := []struct {
+		name string
+		want string
+	}{
+		{"Kyber512", "e9c2bd37133fcb40772f81559f14b1f58dccd1c816701be9ba6214d43baf4547"},
+		{"Kyber768", "a1e122cad3c24bc51622e4c242d8b8acbcd3f618fee4220400605ca8f9ea02c2"},
+		{"Kyber1024", "89248f2f33f7f4f7051729111f3049c409a933ec904aedadf035f30fa5646cd5"},
+	}
+	for _, kat := range kats {
+		kat := kat
+		t.Run(kat.name, func(t *testing.T) {
+			k := NewKeyEncapsulation().(*pureKem)
+			if err := k.Init(kat.name, nil); err != nil {
+				t.Fatal(err)
+			}
+			scheme := k.scheme
+			var seed [48]byte
+			for i := range seed {
+				seed[i] = byte(i)
+			}
+			kseed := make([]byte, scheme.SeedSize())
+			eseed := make([]byte, scheme.EncapsulationSeedSize())
+			f := sha256.New()
+			g := newKatDRBG(&seed)
+			fmt.Fprintf(f, "# %s\n\n", kat.name)
+			for i := 0; i < 100; i++ {
+				g.fill(seed[:])
+				fmt.Fprintf(f, "count = %d\n", i)
+				fmt.Fprintf(f, "seed = %X\n", seed)
+				g2 := newKatDRBG(&seed)
+				// the reference keypair calls randombytes twice
+				g2.fill(kseed[:32])
+				g2.fill(kseed[32:])
+				g2.fill(eseed)
+
+				ppk, err := k.storeKeyPair(scheme.DeriveKeyPair(kseed))
+				if err != nil {
+					t.Fatal(err)
+				}
+				pk, err := scheme.UnmarshalBinaryPublicKey(ppk)
+				if err != nil {
+					t.Fatal(err)
+				}
+				ct, ss, err := scheme.EncapsulateDeterministically(pk, eseed)
+				if err != nil {
+					t.Fatal(err)
+				}
+				ss2, err := k.DecapSecret(ct)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(ss, ss2) {
+					t.Fatalf("count %d: shared secrets differ", i)
+				}
+				fmt.Fprintf(f, "pk = %X\n", ppk)
+				fmt.Fprintf(f, "sk = %X\n", k.ExportSecretKey())
+				fmt.Fprintf(f, "ct = %X\n", ct)
+				fmt.Fprintf(f, "ss = %X\n\n", ss)
+			}
+			if got := fmt.Sprintf("%x", f.Sum(nil)); got != kat.want {
+				t.Fatalf("KAT digest mismatch: got %s want %s", got, kat.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+//go:build liboqs
+// +build liboqs
+
+package crypto
+
+import (
+	oqs "github.com/open-quantum-safe/liboqs-go/oqs"
+)
+
+// oqsKem adapts the liboqs-go binding to KeyEncapsulation.
+type oqsKem struct {
+	kem oqs.KeyEncapsulation
+}
+
+// NewKeyEncapsulation returns a KEM backed by liboqs.
+func NewKeyEncapsulation() KeyEncapsulation {
+	return &oqsKem{}
+}
+
+func (o *oqsKem) Init(algName string, secretKey []byte) error {
+	return o.kem.Init(algName, secretKey)
+}
+
+func (o *oqsKem) Details() KemDetails {
+	d := o.kem.Details()
+	return KemDetails{
+		ClaimedNISTLevel:   d.ClaimedNISTLevel,
+		IsINDCCA:           d.IsINDCCA,
+		LengthCiphertext:   d.LengthCiphertext,
+		LengthPublicKey:    d.LengthPublicKey,
+		LengthSecretKey:    d.LengthSecretKey,
+		LengthSharedSecret: d.LengthSharedSecret,
+		Name:               d.Name,
+		Version:            d.Version,
+	}
+}
+
+func (o *oqsKem) GenerateKeyPair() ([]byte, error) {
+	return o.kem.GenerateKeyPair()
+}
+
+func (o *oqsKem) ExportSecretKey() []byte {
+	return o.kem.ExportSecretKey()
+}
+
+func (o *oqsKem) EncapSecret(publicKey []byte) (ciphertext, sharedSecret []byte, err error) {
+	return o.kem.EncapSecret(publicKey)
+}
+
+func (o *oqsKem) DecapSecret(ciphertext []byte) ([]byte, error) {
+	return o.kem.DecapSecret(ciphertext)
+}
+
+func (o *oqsKem) Clean() {
+	o.kem.Clean()
+}
@@ -0,0 +1,128 @@
+//go:build !liboqs
+// +build !liboqs
+
+package crypto
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/cloudflare/circl/kem"
+	"github.com/cloudflare/circl/kem/kyber/kyber1024"
+	"github.com/cloudflare/circl/kem/kyber/kyber512"
+	"github.com/cloudflare/circl/kem/kyber/kyber768"
+)
+
+// pureKemVersion is reported in KemDetails.Version by the pure Go backend.
+const pureKemVersion = "round3 (github.com/cloudflare/circl)"
+
+// pureKemSchemes lists the Kyber parameter sets with the NIST level liboqs
+// claims for them. Names are matched case-insensitively like liboqs does.
+var pureKemSchemes = map[string]struct {
+	scheme kem.Scheme
+	level  int
+}{
+	"kyber512":  {kyber512.Scheme(), 1},
+	"kyber768":  {kyber768.Scheme(), 3},
+	"kyber1024": {kyber1024.Scheme(), 5},
+}
+
+// pureKem is the pure Go KeyEncapsulation used when liboqs is not linked in.
+type pureKem struct {
+	scheme    kem.Scheme
+	details   KemDetails
+	secretKey []byte
+}
+
+// NewKeyEncapsulation returns a KEM backed by the pure Go Kyber implementation.
+func NewKeyEncapsulation() KeyEncapsulation {
+	return &pureKem{}
+}
+
+func (p *pureKem) Init(algName string, secretKey []byte) error {
+	s, ok := pureKemSchemes[strings.ToLower(algName)]
+	if !ok {
+		return errors.New("can not init KEM: " + algName + " is not supported")
+	}
+	p.scheme = s.scheme
+	p.details = KemDetails{
+		ClaimedNISTLevel:   s.level,
+		IsINDCCA:           true,
+		LengthCiphertext:   s.scheme.CiphertextSize(),
+		LengthPublicKey:    s.scheme.PublicKeySize(),
+		LengthSecretKey:    s.scheme.PrivateKeySize(),
+		LengthSharedSecret: s.scheme.SharedKeySize(),
+		Name:               s.scheme.Name(),
+		Version:            pureKemVersion,
+	}
+	p.secretKey = append([]byte(nil), secretKey...)
+	return nil
+}
+
+func (p *pureKem) Details() KemDetails {
+	return p.details
+}
+
+func (p *pureKem) GenerateKeyPair() ([]byte, error) {
+	if p.scheme == nil {
+		return nil, errors.New("KEM is not initialised")
+	}
+	pk, sk, err := p.scheme.GenerateKeyPair()
+	if err != nil {
+		return nil, err
+	}
+	return p.storeKeyPair(pk, sk)
+}
+
+// storeKeyPair keeps the packed secret key and returns the packed public key.
+func (p *pureKem) storeKeyPair(pk kem.PublicKey, sk kem.PrivateKey) ([]byte, error) {
+	skb, err := sk.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	p.secretKey = skb
+	return pk.MarshalBinary()
+}
+
+func (p *pureKem) ExportSecretKey() []byte {
+	return p.secretKey
+}
+
+func (p *pureKem) EncapSecret(publicKey []byte) (ciphertext, sharedSecret []byte, err error) {
+	if p.scheme == nil {
+		return nil, nil, errors.New("KEM is not initialised")
+	}
+	if len(publicKey) != p.details.LengthPublicKey {
+		return nil, nil, errors.New("incorrect public key length")
+	}
+	pk, err := p.scheme.UnmarshalBinaryPublicKey(publicKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return p.scheme.Encapsulate(pk)
+}
+
+func (p *pureKem) DecapSecret(ciphertext []byte) ([]byte, error) {
+	if p.scheme == nil {
+		return nil, errors.New("KEM is not initialised")
+	}
+	if len(ciphertext) != p.details.LengthCiphertext {
+		return nil, errors.New("incorrect ciphertext length")
+	}
+	if len(p.secretKey) != p.details.LengthSecretKey {
+		return nil, errors.New("incorrect secret key length, make sure you " +
+			"specify one in Init() or run GenerateKeyPair()")
+	}
+	sk, err := p.scheme.UnmarshalBinaryPrivateKey(p.secretKey)
+	if err != nil {
+		return nil, err
+	}
+	return p.scheme.Decapsulate(sk, ciphertext)
+}
+
+func (p *pureKem) Clean() {
+	for i := range p.secretKey {
+		p.secretKey[i] = 0
+	}
+	*p = pureKem{}
+}
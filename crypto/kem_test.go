@@ -0,0 +1,45 @@
+package crypto
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestKeyEncapsulation(t *testing.T) {
+	client := NewKemClient()
+	server := NewKemServer()
+	defer client.Client.Clean()
+	defer server.Server.Clean()
+
+	d := server.Server.Details()
+	if len(client.PubKey) != d.LengthPublicKey {
+		t.Fatalf("public key is %d bytes, want %d", len(client.PubKey), d.LengthPublicKey)
+	}
+	ct, ss, err := server.Server.EncapSecret(client.PubKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ct) != d.LengthCiphertext || len(ss) != d.LengthSharedSecret {
+		t.Fatalf("unexpected sizes: ciphertext %d, shared secret %d", len(ct), len(ss))
+	}
+	ss2, err := client.Client.DecapSecret(ct)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(ss, ss2) {
+		t.Fatal("client and server shared secrets differ")
+	}
+
+	// a KEM initialised with the exported secret key decapsulates the same way
+	restored := NewKeyEncapsulation()
+	if err := restored.Init(KEMNAME, client.Client.ExportSecretKey()); err != nil {
+		t.Fatal(err)
+	}
+	ss3, err := restored.DecapSecret(ct)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(ss, ss3) {
+		t.Fatal("restored secret key decapsulated a different secret")
+	}
+}
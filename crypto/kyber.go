@@ -6,6 +6,7 @@ import (
 
 	"go.dedis.ch/kyber/v3/group/edwards25519"
 	"go.dedis.ch/kyber/v3/xof/blake2xb"
+	"lukechampine.com/frand"
 )
 
 type HardenedKeys struct {
@@ -26,7 +27,8 @@ func RestorePrivateKey(b []byte) {
 }
 
 func GenerateHardenedKeys() *HardenedKeys {
-	rng := blake2xb.New(nil)
+	// the XOF must be seeded, blake2xb.New(nil) yields the same key every time
+	rng := blake2xb.New(frand.Bytes(32))
 	suite := edwards25519.NewBlakeSHA256Ed25519WithRand(rng)
 	h := &HardenedKeys{}
 	sk := suite.Scalar().Pick(rng)   // private key
@@ -39,8 +41,14 @@ func GenerateHardenedKeys() *HardenedKeys {
 
 func GenerateAndVerifySharedKeys(h1 *HardenedKeys, h2 *HardenedKeys) (secret string, err error) {
 
-	S1 := h1.Suite.Point().Mul(h1.PrivKey, h2.PubKey)
-	S2 := h2.Suite.Point().Mul(h2.PrivKey, h1.PubKey)
+	S1, err := SharedPoint(h1.PrivKey, h2.PubKey)
+	if err != nil {
+		return "", err
+	}
+	S2, err := SharedPoint(h2.PrivKey, h1.PubKey)
+	if err != nil {
+		return "", err
+	}
 
 	if !S1.Equal(S2) {
 		err = errors.New("shared secrets exchange didn't work")
@@ -50,3 +58,18 @@ func GenerateAndVerifySharedKeys(h1 *HardenedKeys, h2 *HardenedKeys) (secret str
 	return S1.String(), nil
 
 }
+
+// HardenedKeysFromPrivateKey rebuilds the key pair of a marshalled private
+// scalar.
+func HardenedKeysFromPrivateKey(sk []byte) (*HardenedKeys, error) {
+	s := hdSuite.Scalar()
+	if err := s.UnmarshalBinary(sk); err != nil {
+		return nil, err
+	}
+	return &HardenedKeys{
+		Group:   hdSuite,
+		PubKey:  hdSuite.Point().Mul(s, nil),
+		PrivKey: s,
+		Suite:   hdSuite,
+	}, nil
+}
@@ -2,6 +2,9 @@ package crypto
 
 import (
 	"encoding/hex"
+	"errors"
+
+	"go.dedis.ch/kyber/v3"
 	"go.dedis.ch/kyber/v3/sign/schnorr"
 )
 
@@ -26,3 +29,36 @@ func (h *HardenedKeys) VerifySignature(msg, signature []byte) bool {
 	}
 	return true
 }
+
+// PublicKeyFromBytes decodes a marshalled edwards25519 public key.
+func PublicKeyFromBytes(b []byte) (kyber.Point, error) {
+	p := hdSuite.Point()
+	if err := p.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// VerifySchnorr checks a signature made with HardenedKeys.Sign.
+func VerifySchnorr(pub kyber.Point, msg, signature []byte) bool {
+	return schnorr.Verify(hdSuite, pub, msg, signature) == nil
+}
+
+// domainMessage binds msg to a signing domain, e.g. "tx" or "vote", so a
+// signature made for one use can not be replayed as another.
+func domainMessage(domain string, msg []byte) []byte {
+	return deriveKey("quantos-signature", []byte(domain), msg)
+}
+
+// SignDomain signs msg under domain.
+func (h *HardenedKeys) SignDomain(domain string, msg []byte) ([]byte, error) {
+	if domain == "" {
+		return nil, errors.New("quantos crypto: empty signature domain")
+	}
+	return schnorr.Sign(hdSuite, h.PrivKey, domainMessage(domain, msg))
+}
+
+// VerifyDomain checks a signature made with SignDomain.
+func VerifyDomain(pub kyber.Point, domain string, msg, signature []byte) bool {
+	return domain != "" && VerifySchnorr(pub, domainMessage(domain, msg), signature)
+}
@@ -0,0 +1,219 @@
+package crypto
+
+import (
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v3"
+	dkg "go.dedis.ch/kyber/v3/share/dkg/pedersen"
+	"go.dedis.ch/kyber/v3/sign/dss"
+)
+
+/*
+
+	Threshold custody
+
+	A t-of-n group of parties jointly owns a key that never exists in one
+	place. The group key is created with a Pedersen distributed key
+	generation (DKG), each party ending up with a share of the secret.
+	Signing uses the distributed Schnorr signature scheme (DSS): every
+	signature needs a fresh DKG for the one-time nonce, after which any t
+	parties issue partial signatures that combine into a regular Ed25519
+	compatible Schnorr signature, verifiable with the group public key.
+
+	Every message between parties belongs to a session:
+
+	ThresholdKeySession  the long-term group key
+	any other string     the nonce of one signature
+
+*/
+
+const ThresholdKeySession = "longterm"
+
+// DKGMessage carries exactly one of Deal, Response or Justification.
+// Deals are sent to a single party, the others are broadcast (To == -1).
+type DKGMessage struct {
+	Session       string
+	From          int
+	To            int
+	Deal          *dkg.Deal
+	Response      *dkg.Response
+	Justification *dkg.Justification
+}
+
+// ThresholdParty is one participant of a threshold custody group. Its
+// HardenedKeys are the long-term identity used to authenticate and encrypt
+// the DKG deals.
+type ThresholdParty struct {
+	Index        int
+	keys         *HardenedKeys
+	participants []kyber.Point
+	t            int
+	generators   map[string]*dkg.DistKeyGenerator
+	shares       map[string]*dkg.DistKeyShare
+	signers      map[string]*dss.DSS
+}
+
+func NewThresholdParty(keys *HardenedKeys, index int, participants []kyber.Point, t int) (*ThresholdParty, error) {
+	if index < 0 || index >= len(participants) || !participants[index].Equal(keys.PubKey) {
+		return nil, errors.New("threshold: party key is not in the participants list")
+	}
+	if t < 1 || t > len(participants) {
+		return nil, fmt.Errorf("threshold: invalid threshold %d of %d", t, len(participants))
+	}
+	return &ThresholdParty{
+		Index:        index,
+		keys:         keys,
+		participants: participants,
+		t:            t,
+		generators:   make(map[string]*dkg.DistKeyGenerator),
+		shares:       make(map[string]*dkg.DistKeyShare),
+		signers:      make(map[string]*dss.DSS),
+	}, nil
+}
+
+// StartDKG opens a DKG session and returns the deals to send to the other
+// parties.
+func (p *ThresholdParty) StartDKG(session string) ([]*DKGMessage, error) {
+	if _, ok := p.generators[session]; ok {
+		return nil, errors.New("threshold: session " + session + " already started")
+	}
+	gen, err := dkg.NewDistKeyGenerator(p.keys.Suite, p.keys.PrivKey, p.participants, p.t)
+	if err != nil {
+		return nil, err
+	}
+	p.generators[session] = gen
+	deals, err := gen.Deals()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*DKGMessage, 0, len(deals))
+	for to, deal := range deals {
+		out = append(out, &DKGMessage{Session: session, From: p.Index, To: to, Deal: deal})
+	}
+	return out, nil
+}
+
+// ProcessDKGMessage handles a message of a started session and returns the
+// messages to broadcast in reply.
+func (p *ThresholdParty) ProcessDKGMessage(m *DKGMessage) ([]*DKGMessage, error) {
+	gen, ok := p.generators[m.Session]
+	if !ok {
+		return nil, errors.New("threshold: unknown session " + m.Session)
+	}
+	switch {
+	case m.Deal != nil:
+		resp, err := gen.ProcessDeal(m.Deal)
+		if err != nil {
+			return nil, err
+		}
+		return []*DKGMessage{{Session: m.Session, From: p.Index, To: -1, Response: resp}}, nil
+	case m.Response != nil:
+		j, err := gen.ProcessResponse(m.Response)
+		if err != nil {
+			return nil, err
+		}
+		if j == nil {
+			return nil, nil
+		}
+		return []*DKGMessage{{Session: m.Session, From: p.Index, To: -1, Justification: j}}, nil
+	case m.Justification != nil:
+		return nil, gen.ProcessJustification(m.Justification)
+	}
+	return nil, errors.New("threshold: empty DKG message")
+}
+
+// FinishDKG stores the party's share once the session is certified. With
+// timeout set the parties that did not answer are excluded and the session
+// only needs a threshold of certified deals.
+func (p *ThresholdParty) FinishDKG(session string, timeout bool) (kyber.Point, error) {
+	gen, ok := p.generators[session]
+	if !ok {
+		return nil, errors.New("threshold: unknown session " + session)
+	}
+	if timeout {
+		gen.SetTimeout()
+	}
+	if !gen.Certified() && !(timeout && gen.ThresholdCertified()) {
+		return nil, errors.New("threshold: session " + session + " is not certified")
+	}
+	share, err := gen.DistKeyShare()
+	if err != nil {
+		return nil, err
+	}
+	p.shares[session] = share
+	delete(p.generators, session)
+	return share.Public(), nil
+}
+
+// PublicKey returns the group public key once the long-term DKG is done.
+func (p *ThresholdParty) PublicKey() kyber.Point {
+	share, ok := p.shares[ThresholdKeySession]
+	if !ok {
+		return nil
+	}
+	return share.Public()
+}
+
+// PartialSign issues the party's partial signature of msg. The nonce DKG of
+// session must be finished.
+func (p *ThresholdParty) PartialSign(session string, msg []byte) (*dss.PartialSig, error) {
+	long, ok := p.shares[ThresholdKeySession]
+	if !ok {
+		return nil, errors.New("threshold: group key is not generated")
+	}
+	random, ok := p.shares[session]
+	if !ok || session == ThresholdKeySession {
+		return nil, errors.New("threshold: no nonce for session " + session)
+	}
+	signer, err := dss.NewDSS(p.keys.Suite, p.keys.PrivKey, p.participants, long, random, msg, p.t)
+	if err != nil {
+		return nil, err
+	}
+	p.signers[session] = signer
+	// a nonce must never be used twice
+	delete(p.shares, session)
+	return signer.PartialSig()
+}
+
+// ProcessPartialSig collects the partial signature of another party.
+func (p *ThresholdParty) ProcessPartialSig(session string, ps *dss.PartialSig) error {
+	signer, ok := p.signers[session]
+	if !ok {
+		return errors.New("threshold: not signing in session " + session)
+	}
+	return signer.ProcessPartialSig(ps)
+}
+
+// Signature combines the collected partial signatures once there are at
+// least t of them.
+func (p *ThresholdParty) Signature(session string) ([]byte, error) {
+	signer, ok := p.signers[session]
+	if !ok {
+		return nil, errors.New("threshold: not signing in session " + session)
+	}
+	if !signer.EnoughPartialSig() {
+		return nil, errors.New("threshold: not enough partial signatures")
+	}
+	delete(p.signers, session)
+	return signer.Signature()
+}
+
+// Forget drops what the party holds of a signing session: its DKG state,
+// nonce share and signer. Parties that were not asked to sign keep their
+// nonce share until they forget the session. The group key is kept.
+func (p *ThresholdParty) Forget(session string) {
+	if session == ThresholdKeySession {
+		return
+	}
+	delete(p.generators, session)
+	delete(p.shares, session)
+	delete(p.signers, session)
+}
+
+// VerifyThresholdSignature verifies a signature of the group key. It is a
+// plain Schnorr (EdDSA) signature, so verifiers do not need to know it was
+// produced by a group.
+func VerifyThresholdSignature(public kyber.Point, msg, sig []byte) bool {
+	return dss.Verify(public, msg, sig) == nil
+}
@@ -0,0 +1,139 @@
+package crypto
+
+import (
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/sign/dss"
+)
+
+// ThresholdGroup runs n ThresholdParty in-process and routes their messages
+// through a local queue. It is meant for simulations and tests of the
+// custody flows; Offline parties neither send nor receive anything.
+type ThresholdGroup struct {
+	Parties []*ThresholdParty
+	Offline map[int]bool
+	queue   []*DKGMessage
+	nonce   uint64
+}
+
+func NewThresholdGroup(n, t int) (*ThresholdGroup, error) {
+	keys := make([]*HardenedKeys, n)
+	participants := make([]kyber.Point, n)
+	for i := range keys {
+		keys[i] = GenerateHardenedKeys()
+		participants[i] = keys[i].PubKey
+	}
+	g := &ThresholdGroup{
+		Parties: make([]*ThresholdParty, n),
+		Offline: make(map[int]bool),
+	}
+	for i := range keys {
+		p, err := NewThresholdParty(keys[i], i, participants, t)
+		if err != nil {
+			return nil, err
+		}
+		g.Parties[i] = p
+	}
+	return g, nil
+}
+
+func (g *ThresholdGroup) online() []*ThresholdParty {
+	var parties []*ThresholdParty
+	for _, p := range g.Parties {
+		if !g.Offline[p.Index] {
+			parties = append(parties, p)
+		}
+	}
+	return parties
+}
+
+// deliver routes queued messages until no party has anything left to say.
+func (g *ThresholdGroup) deliver() error {
+	for len(g.queue) > 0 {
+		m := g.queue[0]
+		g.queue = g.queue[1:]
+		for _, p := range g.online() {
+			if p.Index == m.From || (m.To != -1 && m.To != p.Index) {
+				continue
+			}
+			out, err := p.ProcessDKGMessage(m)
+			if err != nil {
+				return fmt.Errorf("party %d: %w", p.Index, err)
+			}
+			g.queue = append(g.queue, out...)
+		}
+	}
+	return nil
+}
+
+// RunDKG runs a full DKG session between the online parties. When some
+// parties are offline the session is closed with a timeout.
+func (g *ThresholdGroup) RunDKG(session string) (kyber.Point, error) {
+	parties := g.online()
+	for _, p := range parties {
+		deals, err := p.StartDKG(session)
+		if err != nil {
+			return nil, err
+		}
+		g.queue = append(g.queue, deals...)
+	}
+	if err := g.deliver(); err != nil {
+		return nil, err
+	}
+	var public kyber.Point
+	for _, p := range parties {
+		pub, err := p.FinishDKG(session, len(g.Offline) > 0)
+		if err != nil {
+			return nil, fmt.Errorf("party %d: %w", p.Index, err)
+		}
+		if public != nil && !public.Equal(pub) {
+			return nil, errors.New("threshold: parties disagree on the DKG public key")
+		}
+		public = pub
+	}
+	return public, nil
+}
+
+// GenerateKey creates the long-term group key.
+func (g *ThresholdGroup) GenerateKey() (kyber.Point, error) {
+	return g.RunDKG(ThresholdKeySession)
+}
+
+// Sign has the given signers produce a signature of msg with the group key.
+// A fresh nonce DKG is run between the online parties first, every party
+// forgets it before Sign returns.
+func (g *ThresholdGroup) Sign(msg []byte, signers []int) ([]byte, error) {
+	g.nonce++
+	session := fmt.Sprintf("nonce-%d", g.nonce)
+	defer func() {
+		for _, p := range g.Parties {
+			p.Forget(session)
+		}
+	}()
+	if _, err := g.RunDKG(session); err != nil {
+		return nil, err
+	}
+	partials := make([]*dss.PartialSig, 0, len(signers))
+	for _, i := range signers {
+		if g.Offline[i] {
+			return nil, fmt.Errorf("threshold: signer %d is offline", i)
+		}
+		ps, err := g.Parties[i].PartialSign(session, msg)
+		if err != nil {
+			return nil, fmt.Errorf("party %d: %w", i, err)
+		}
+		partials = append(partials, ps)
+	}
+	if len(signers) == 0 {
+		return nil, errors.New("threshold: no signers")
+	}
+	combiner := g.Parties[signers[0]]
+	for _, ps := range partials[1:] {
+		if err := combiner.ProcessPartialSig(session, ps); err != nil {
+			return nil, err
+		}
+	}
+	return combiner.Signature(session)
+}
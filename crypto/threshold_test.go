@@ -0,0 +1,64 @@
+package crypto
+
+import (
+	"testing"
+
+	"go.dedis.ch/kyber/v3/sign/schnorr"
+)
+
+func TestThresholdSigning(t *testing.T) {
+	g, err := NewThresholdGroup(5, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := g.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := []byte("treasury transfer")
+
+	sig, err := g.Sign(msg, []int{0, 2, 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !VerifyThresholdSignature(pub, msg, sig) {
+		t.Fatal("threshold signature does not verify")
+	}
+	// the group signature is a regular schnorr signature
+	if err := schnorr.Verify(g.Parties[0].keys.Suite, pub, msg, sig); err != nil {
+		t.Fatal(err)
+	}
+	if VerifyThresholdSignature(pub, []byte("another transfer"), sig) {
+		t.Fatal("threshold signature verified for the wrong message")
+	}
+
+	if _, err := g.Sign(msg, []int{1, 3}); err == nil {
+		t.Fatal("two parties out of five signed with a threshold of three")
+	}
+	for _, p := range g.Parties {
+		if len(p.generators) != 0 || len(p.shares) != 1 || len(p.signers) != 0 {
+			t.Fatalf("party %d keeps nonce material after signing", p.Index)
+		}
+	}
+}
+
+func TestThresholdSigningWithOfflineParty(t *testing.T) {
+	g, err := NewThresholdGroup(5, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := g.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Offline[1] = true
+	g.Offline[3] = true
+	msg := []byte("treasury transfer")
+	sig, err := g.Sign(msg, []int{0, 2, 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !VerifyThresholdSignature(pub, msg, sig) {
+		t.Fatal("threshold signature does not verify")
+	}
+}
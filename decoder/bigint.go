@@ -0,0 +1,129 @@
+package decoder
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+
+	"github.com/holiman/uint256"
+	"github.com/quantosnetwork/Quantos/uint512"
+)
+
+// MaxBigIntDigits bounds n<n>e integers, parsing them is quadratic in
+// their length. 1234 digits hold 4096 bits.
+const MaxBigIntDigits = 1234
+
+var (
+	maxUint256 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	maxUint512 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 512), big.NewInt(1))
+)
+
+// decodeBig reads n<n>e as a *big.Int.
+func (d *Decoder) decodeBig() (*big.Int, error) {
+	d.cursor += 1
+	index := bytes.IndexByte(d.data[d.cursor:], 'e')
+	if index == -1 {
+		return nil, errors.New("quantos int decoder: invalid integer field")
+	}
+	digits := d.data[d.cursor : d.cursor+index]
+	if len(digits) > MaxBigIntDigits {
+		return nil, errors.New("quantos int parser: invalid length of number")
+	}
+	negative := len(digits) > 0 && digits[0] == '-'
+	abs := digits
+	if negative {
+		abs = digits[1:]
+	}
+	if len(abs) == 0 {
+		return nil, errors.New("quantos int parser: empty number")
+	}
+	for _, c := range abs {
+		if c < '0' || c > '9' {
+			return nil, errors.New("quantos int parser: invalid integer byte: " + string(c))
+		}
+	}
+	if d.Strict && abs[0] == '0' {
+		return nil, ErrNonCanonical
+	}
+	x, _ := new(big.Int).SetString(string(digits), 10)
+	if d.Strict && x.IsInt64() {
+		return nil, ErrNonCanonical
+	}
+	d.cursor += index + 1
+	return x, nil
+}
+
+// decodeInteger reads i<n>e or n<n>e, setting small or large.
+func (d *Decoder) decodeInteger() (small int64, large *big.Int, err error) {
+	if d.cursor >= d.length {
+		return 0, nil, ErrUnexpectedEnd
+	}
+	switch d.data[d.cursor] {
+	case 'i':
+		v, err := d.decodeInt()
+		if err != nil {
+			return 0, nil, err
+		}
+		return v.(int64), nil, nil
+	case 'n':
+		large, err = d.decodeBig()
+		return 0, large, err
+	}
+	return 0, nil, errors.New("quantos int decoder: invalid integer field")
+}
+
+// DecodeBigInt reads any integer into z.
+func (d *Decoder) DecodeBigInt(z *big.Int) error {
+	small, large, err := d.decodeInteger()
+	if err != nil {
+		return err
+	}
+	if large != nil {
+		z.Set(large)
+	} else {
+		z.SetInt64(small)
+	}
+	return nil
+}
+
+// DecodeUint256 reads a non negative integer below 2^256 into z.
+func (d *Decoder) DecodeUint256(z *uint256.Int) error {
+	small, large, err := d.decodeInteger()
+	if err != nil {
+		return err
+	}
+	return setUint256(z, small, large)
+}
+
+// DecodeUint512 reads a non negative integer below 2^512 into z.
+func (d *Decoder) DecodeUint512(z *uint512.Int) error {
+	small, large, err := d.decodeInteger()
+	if err != nil {
+		return err
+	}
+	return setUint512(z, small, large)
+}
+
+func setUint256(z *uint256.Int, small int64, large *big.Int) error {
+	if large == nil && small >= 0 {
+		z.SetUint64(uint64(small))
+		return nil
+	}
+	if large == nil || large.Sign() < 0 || large.Cmp(maxUint256) > 0 {
+		return errors.New("quantos decoding: integer overflows uint256")
+	}
+	z.SetFromBig(large)
+	return nil
+}
+
+func setUint512(z *uint512.Int, small int64, large *big.Int) error {
+	if large == nil && small >= 0 {
+		z.SetUint64(uint64(small))
+		return nil
+	}
+	if large == nil || large.Sign() < 0 || large.Cmp(maxUint512) > 0 {
+		return errors.New("quantos decoding: integer overflows uint512")
+	}
+	z.SetFromBig(large)
+	return nil
+}
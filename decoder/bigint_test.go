@@ -0,0 +1,110 @@
+package decoder
+
+import (
+	"math"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/quantosnetwork/Quantos/encoder"
+	"github.com/quantosnetwork/Quantos/uint512"
+)
+
+type amounts struct {
+	Nonce   uint64       `quantos:"nonce"`
+	Balance uint256.Int  `quantos:"balance"`
+	Fee     *uint256.Int `quantos:"fee"`
+	Product uint512.Int  `quantos:"product"`
+	Delta   *big.Int     `quantos:"delta"`
+	Small   uint8        `quantos:"small"`
+}
+
+func TestBigIntegers(t *testing.T) {
+	maxU256 := new(uint256.Int).SetAllOne()
+	var maxU512 uint512.Int
+	maxU512.Not(&maxU512)
+	in := &amounts{
+		Nonce:   math.MaxUint64,
+		Balance: *maxU256,
+		Fee:     uint256.NewInt(7),
+		Product: maxU512,
+		Delta:   new(big.Int).Neg(maxU512.ToBig()),
+		Small:   200,
+	}
+	b, err := encoder.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "5:noncen18446744073709551615e") || !strings.Contains(string(b), "3:feei7e") {
+		t.Fatalf("unexpected encoding %q", b)
+	}
+	if err := Validate(b); err != nil {
+		t.Fatal(err)
+	}
+	var out amounts
+	if err := Unmarshal(b, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(in, &out) {
+		t.Fatalf("got %+v", out)
+	}
+
+	var d Decoder
+	for _, c := range []struct {
+		in   interface{}
+		want string
+	}{
+		{uint64(math.MaxInt64), "i9223372036854775807e"},
+		{uint64(math.MaxInt64 + 1), "n9223372036854775808e"},
+		{uint(math.MaxUint64), "n18446744073709551615e"},
+		{big.NewInt(-5), "i-5e"},
+		{new(big.Int).Lsh(big.NewInt(-1), 63), "i-9223372036854775808e"},
+		{new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(-1), 63), big.NewInt(1)), "n-9223372036854775809e"},
+		{uint512.NewInt(1), "i1e"},
+	} {
+		b, err := encoder.Marshal(c.in)
+		if err != nil || string(b) != c.want {
+			t.Errorf("%v: got %q %v want %q", c.in, b, err, c.want)
+		}
+		v, err := d.Decode(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		again, _ := encoder.Marshal(v)
+		if string(again) != c.want {
+			t.Errorf("%q decodes to %v", c.want, v)
+		}
+	}
+}
+
+func TestBigIntegerErrors(t *testing.T) {
+	over := new(big.Int).Lsh(big.NewInt(1), 256)
+	b, _ := encoder.Marshal(map[string]interface{}{"balance": over})
+	var a amounts
+	if err := Unmarshal(b, &a); err == nil {
+		t.Fatal("2^256 decoded into a uint256")
+	}
+	b, _ = encoder.Marshal(map[string]interface{}{"small": uint64(math.MaxUint64)})
+	if err := Unmarshal(b, &a); err == nil {
+		t.Fatal("2^64-1 decoded into a uint8")
+	}
+	var signed struct{ V int64 }
+	b, _ = encoder.Marshal(map[string]interface{}{"V": uint64(math.MaxUint64)})
+	if err := Unmarshal(b, &signed); err == nil {
+		t.Fatal("2^64-1 decoded into an int64")
+	}
+
+	for _, in := range []string{"n5e", "n-5e", "n09223372036854775808e", "n-0e"} {
+		if err := Validate([]byte(in)); err != ErrNonCanonical {
+			t.Errorf("%q: got %v", in, err)
+		}
+	}
+	for _, in := range []string{"ne", "n-e", "n1x1e", "n" + strings.Repeat("9", MaxBigIntDigits+1) + "e"} {
+		var d Decoder
+		if _, err := d.Decode([]byte(in)); err == nil {
+			t.Errorf("%.20q decoded", in)
+		}
+	}
+}
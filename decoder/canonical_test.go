@@ -0,0 +1,98 @@
+package decoder
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/quantosnetwork/Quantos/encoder"
+)
+
+func TestStrictRejects(t *testing.T) {
+	for in, want := range map[string]error{
+		"i03e":           ErrNonCanonical,
+		"i-0e":           ErrNonCanonical,
+		"i-03e":          ErrNonCanonical,
+		"i00e":           ErrNonCanonical,
+		"03:abc":         ErrNonCanonical,
+		"d1:bi1e1:ai1ee": ErrKeyOrder,
+		"d1:ai1e1:ai1ee": ErrKeyOrder,
+		"hi2ei0ei1ei0ee": ErrKeyOrder,
+		"hi1ei0ei1ei0ee": ErrKeyOrder,
+		"i1ei2e":         ErrTrailing,
+		"0:0:":           ErrTrailing,
+	} {
+		d := Decoder{Strict: true}
+		if _, err := d.Decode([]byte(in)); err != want {
+			t.Errorf("%q: got %v want %v", in, err, want)
+		}
+	}
+	for _, in := range []string{"i0e", "i-1e", "i10e", "i-9223372036854775808e", "0:", "10:0123456789", "de", "le", "he", "d0:0:1:a0:e"} {
+		if err := Validate([]byte(in)); err != nil {
+			t.Errorf("%q: %v", in, err)
+		}
+	}
+	for _, in := range []string{"i-e", "ie", "i1-e", "-0:", "1:", "i9223372036854775808e", "i-9223372036854775809e"} {
+		if err := Validate([]byte(in)); err == nil {
+			t.Errorf("%q accepted", in)
+		}
+	}
+}
+
+func TestEncoderIsCanonical(t *testing.T) {
+	for _, v := range []interface{}{
+		int64(0), int64(-1), int64(math.MinInt64), int64(math.MaxInt64), int64(100),
+		"", []byte{0}, []interface{}{}, map[string]interface{}{},
+		map[string]interface{}{"b": int64(1), "a": []interface{}{"x", int64(-10)}, "": "empty"},
+		map[int]interface{}{10: "ten", -10: "minus ten", 0: map[int]interface{}{}},
+		&testBlock{Height: 1, Meta: map[string]string{"z": "", "y": ""}, Reward: *uint256.NewInt(256)},
+	} {
+		b, err := encoder.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := Validate(b); err != nil {
+			t.Fatalf("%q: %v", b, err)
+		}
+	}
+
+	var tx testTx
+	b, _ := encoder.Marshal(testTx{From: "a", Amount: uint256.NewInt(1)})
+	if err := UnmarshalStrict(b, &tx); err != nil || tx.From != "a" {
+		t.Fatal(err)
+	}
+	if err := UnmarshalStrict(append(b, '0'), &tx); err != ErrTrailing {
+		t.Fatalf("got %v", err)
+	}
+}
+
+// FuzzCanonical checks that whatever decodes encodes back to canonical
+// form, and that canonical input is the only encoding of its value.
+func FuzzCanonical(f *testing.F) {
+	for _, seed := range []string{
+		"i0e", "i-42e", "4:spam", "le", "l4:spami42ee", "d3:bar4:spam3:fooi42ee",
+		"hi-1e1:ai2el1:bee", "d1:ad1:bhi0edeeee", "i-0e", "i007e", "d1:bi0e1:ai0ee",
+		"i-9223372036854775808e", "i9999999999999999999e", "n18446744073709551615e",
+		"n-9223372036854775809e", "n5e", "n00e", "ln9223372036854775808ee",
+	} {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var d Decoder
+		v, err := d.Decode(data)
+		if err != nil {
+			return
+		}
+		b, err := encoder.Marshal(v)
+		if err != nil {
+			t.Fatalf("%q decoded but does not encode: %v", data, err)
+		}
+		if err := Validate(b); err != nil {
+			t.Fatalf("encoding %q of %q is not canonical: %v", b, data, err)
+		}
+		if Validate(data) == nil && !bytes.Equal(b, data) {
+			t.Fatalf("canonical %q encodes back as %q", data, b)
+		}
+	})
+}
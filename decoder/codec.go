@@ -0,0 +1,163 @@
+package decoder
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"reflect"
+)
+
+// Unmarshaler is implemented by types that decode themselves, usually
+// with methods generated by quantosgen. It reads one value at the cursor
+// of d.
+type Unmarshaler interface {
+	UnmarshalQuantos(d *Decoder) error
+}
+
+var (
+	ErrUnexpectedEnd = errors.New("quantos decoding: unexpected end of input")
+	ErrLength        = errors.New("quantos decoding: wrong array length")
+)
+
+// Reset points d at the start of data.
+func (d *Decoder) Reset(data []byte) {
+	d.data = data
+	d.length = len(data)
+	d.cursor = 0
+}
+
+func (d *Decoder) expect(c byte) error {
+	if d.cursor >= d.length {
+		return ErrUnexpectedEnd
+	}
+	if d.data[d.cursor] != c {
+		return fmt.Errorf("quantos decoding: expected %q at offset %d, got %q", c, d.cursor, d.data[d.cursor])
+	}
+	d.cursor++
+	return nil
+}
+
+func (d *Decoder) OpenList() error {
+	return d.expect('l')
+}
+
+func (d *Decoder) OpenDict() error {
+	return d.expect('d')
+}
+
+// More reports whether the list or dictionary being read has more items.
+func (d *Decoder) More() bool {
+	return d.cursor < d.length && d.data[d.cursor] != 'e'
+}
+
+// Close reads the end of a list or dictionary.
+func (d *Decoder) Close() error {
+	return d.expect('e')
+}
+
+// Finish checks that the whole input was read, ErrTrailing if not.
+func (d *Decoder) Finish() error {
+	if d.cursor != d.length {
+		return ErrTrailing
+	}
+	return nil
+}
+
+// DecodeInt reads an integer that fits in bitSize bits, 0 meaning int,
+// like strconv.ParseInt.
+func (d *Decoder) DecodeInt(bitSize int) (int64, error) {
+	if d.cursor >= d.length {
+		return 0, ErrUnexpectedEnd
+	}
+	if d.data[d.cursor] == 'n' {
+		return 0, fmt.Errorf("quantos decoding: integer overflows int%d", bitSize)
+	}
+	if d.data[d.cursor] != 'i' {
+		return 0, errors.New("quantos int decoder: invalid integer field")
+	}
+	v, err := d.decodeInt()
+	if err != nil {
+		return 0, err
+	}
+	n := v.(int64)
+	if bitSize == 0 {
+		bitSize = bits.UintSize
+	}
+	if bitSize < 64 && (n < -1<<(bitSize-1) || n >= 1<<(bitSize-1)) {
+		return 0, fmt.Errorf("quantos decoding: %d overflows int%d", n, bitSize)
+	}
+	return n, nil
+}
+
+// DecodeUint reads a non negative integer that fits in bitSize bits.
+func (d *Decoder) DecodeUint(bitSize int) (uint64, error) {
+	small, large, err := d.decodeInteger()
+	if err != nil {
+		return 0, err
+	}
+	if bitSize == 0 {
+		bitSize = bits.UintSize
+	}
+	if large != nil {
+		if bitSize < 64 || large.Sign() < 0 || !large.IsUint64() {
+			return 0, fmt.Errorf("quantos decoding: %s overflows uint%d", large, bitSize)
+		}
+		return large.Uint64(), nil
+	}
+	if small < 0 || bitSize < 64 && uint64(small) >= 1<<bitSize {
+		return 0, fmt.Errorf("quantos decoding: %d overflows uint%d", small, bitSize)
+	}
+	return uint64(small), nil
+}
+
+func (d *Decoder) DecodeBool() (bool, error) {
+	n, err := d.DecodeInt(64)
+	if err != nil {
+		return false, err
+	}
+	if n != 0 && n != 1 {
+		return false, fmt.Errorf("quantos decoding: %d is not a bool", n)
+	}
+	return n == 1, nil
+}
+
+// DecodeBytes reads a byte string. It aliases the input.
+func (d *Decoder) DecodeBytes() ([]byte, error) {
+	return d.decodeBytes()
+}
+
+// DecodeByteArray reads a byte string of exactly len(dst) bytes into dst.
+func (d *Decoder) DecodeByteArray(dst []byte) error {
+	b, err := d.DecodeBytes()
+	if err != nil {
+		return err
+	}
+	if len(b) != len(dst) {
+		return ErrLength
+	}
+	copy(dst, b)
+	return nil
+}
+
+// Skip reads past the next value.
+func (d *Decoder) Skip() error {
+	_, err := d.decode()
+	return err
+}
+
+// DecodeValue reads the next value into the one v points to, like
+// Unmarshal.
+func (d *Decoder) DecodeValue(v interface{}) error {
+	if u, ok := v.(Unmarshaler); ok {
+		return u.UnmarshalQuantos(d)
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.New("quantos decoding: DecodeValue needs a non-nil pointer")
+	}
+	value, err := d.decode()
+	if err != nil {
+		return err
+	}
+	return assign(rv.Elem(), value)
+}
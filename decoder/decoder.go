@@ -7,22 +7,71 @@ import (
 	"strconv"
 )
 
+// DefaultMaxDepth is how deep lists, dictionaries and hashtables may nest
+// unless the decoder says otherwise.
+const DefaultMaxDepth = 64
+
 type Decoder struct {
+	// MaxDepth limits nesting, DefaultMaxDepth when zero.
+	MaxDepth int
+	// Strict only accepts the canonical encoding, the one the encoder
+	// writes, see Validate.
+	Strict bool
+
 	data   []byte
 	length int
 	cursor int
+	depth  int
 }
 
+var (
+	ErrTooDeep      = errors.New("quantos decoding: nesting too deep")
+	ErrNonCanonical = errors.New("quantos decoding: non-canonical number")
+)
+
 func (d *Decoder) Decode(data []byte) (interface{}, error) {
-	d.data = data
-	d.length = len(data)
-	return d.decode()
+	d.Reset(data)
+	if d.length == 0 {
+		return nil, ErrUnexpectedEnd
+	}
+	v, err := d.decode()
+	if err == nil && d.Strict && d.cursor != d.length {
+		return nil, ErrTrailing
+	}
+	return v, err
+}
+
+func (d *Decoder) maxDepth() int {
+	if d.MaxDepth > 0 {
+		return d.MaxDepth
+	}
+	return DefaultMaxDepth
 }
 
 func (d *Decoder) decode() (interface{}, error) {
+	if d.cursor >= d.length {
+		return nil, ErrUnexpectedEnd
+	}
 	switch d.data[d.cursor] {
 	case 'i':
 		return d.decodeInt()
+	case 'n':
+		return d.decodeBig()
+	case 'l', 'd', 'h':
+		if d.depth >= d.maxDepth() {
+			return nil, ErrTooDeep
+		}
+		d.depth++
+		v, err := d.decodeContainer()
+		d.depth--
+		return v, err
+	default:
+		return d.decodeBytes()
+	}
+}
+
+func (d *Decoder) decodeContainer() (interface{}, error) {
+	switch d.data[d.cursor] {
 	case 'l':
 		d.cursor += 1
 		list := []interface{}{}
@@ -43,6 +92,7 @@ func (d *Decoder) decode() (interface{}, error) {
 	case 'd':
 		d.cursor += 1
 		dictionary := map[string]interface{}{}
+		var prev []byte
 		for {
 			if d.cursor == d.length {
 				return nil, errors.New("bencode: invalid dictionary field")
@@ -55,6 +105,10 @@ func (d *Decoder) decode() (interface{}, error) {
 			if err != nil {
 				return nil, errors.New("bencode: non-string dictionary key")
 			}
+			if d.Strict && prev != nil && bytes.Compare(prev, key) >= 0 {
+				return nil, ErrKeyOrder
+			}
+			prev = key
 			value, err := d.decode()
 			if err != nil {
 				return nil, err
@@ -64,6 +118,7 @@ func (d *Decoder) decode() (interface{}, error) {
 	case 'h':
 		d.cursor += 1
 		hashtable := map[int]interface{}{}
+		var prev int
 		for {
 			if d.cursor == d.length {
 				return nil, errors.New("quantos decoding: invalid hashtable")
@@ -72,23 +127,28 @@ func (d *Decoder) decode() (interface{}, error) {
 				d.cursor += 1
 				return hashtable, nil
 			}
-			key, err := d.decodeBytes()
+			key, err := d.DecodeInt(0)
 			if err != nil {
-				return nil, errors.New("bencode: non-string dictionary key")
+				return nil, errors.New("quantos decoding: non-integer hashtable key")
 			}
+			if d.Strict && len(hashtable) > 0 && int(key) <= prev {
+				return nil, ErrKeyOrder
+			}
+			prev = int(key)
 			value, err := d.decode()
 			if err != nil {
 				return nil, err
 			}
-			hashtable[crypto.BytesToInt(key)] = value
+			hashtable[int(key)] = value
 		}
-
-	default:
-		return d.decodeBytes()
 	}
+	return nil, errors.New("quantos decoding: not a container")
 }
 
 func (d *Decoder) decodeBytes() ([]byte, error) {
+	if d.cursor >= d.length {
+		return nil, ErrUnexpectedEnd
+	}
 	if d.data[d.cursor] < '0' || d.data[d.cursor] > '9' {
 		return nil, errors.New("quantos bytes decoder: invalid string field")
 	}
@@ -102,10 +162,10 @@ func (d *Decoder) decodeBytes() ([]byte, error) {
 		return nil, err
 	}
 	index += 1
-	endIndex := index + int(stringLength)
-	if endIndex > d.length {
+	if stringLength < 0 || stringLength > int64(d.length-index) {
 		return nil, errors.New("quantos bytes decoder: not a valid string")
 	}
+	endIndex := index + int(stringLength)
 	value := d.data[index:endIndex]
 	d.cursor = endIndex
 	return value, nil
@@ -120,28 +180,44 @@ var (
 )
 
 func (d *Decoder) parseInt(data []byte) (int64, error) {
+	if len(data) == 0 {
+		return 0, errors.New("quantos int parser: empty number")
+	}
 	isNegative := false
 	if data[0] == '-' {
 		data = data[1:]
 		isNegative = true
+		if len(data) == 0 {
+			return 0, errors.New("quantos int parser: empty number")
+		}
+	}
+	if d.Strict && (len(data) > 1 && data[0] == '0' || isNegative && data[0] == '0') {
+		return 0, ErrNonCanonical
 	}
 	maxDigit := len(data)
 	if maxDigit > pow10i64Len {
 		return 0, errors.New("quantos int parser: invalid length of number")
 	}
-	sum := int64(0)
+	// 19 digits fit in a uint64, the range is checked at the end
+	sum := uint64(0)
 	for i, b := range data {
 		if b < '0' || b > '9' {
 			return 0, errors.New("quantos int parser: invalid integer byte: " + strconv.FormatUint(uint64(b), 10))
 		}
-		c := int64(b) - 48
-		digitValue := pow10i64[maxDigit-i-1]
+		c := uint64(b) - 48
+		digitValue := uint64(pow10i64[maxDigit-i-1])
 		sum += c * digitValue
 	}
 	if isNegative {
-		return -1 * sum, nil
+		if sum > 1<<63 {
+			return 0, errors.New("quantos int parser: number out of range")
+		}
+		return int64(-sum), nil
+	}
+	if sum > 1<<63-1 {
+		return 0, errors.New("quantos int parser: number out of range")
 	}
-	return sum, nil
+	return int64(sum), nil
 }
 
 func (d *Decoder) decodeInt() (interface{}, error) {
@@ -0,0 +1,175 @@
+package decoder
+
+import (
+	"bufio"
+	"errors"
+	"io"
+)
+
+// DefaultMaxSize is the largest encoded value a StreamDecoder reads unless
+// told otherwise.
+const DefaultMaxSize = 16 << 20
+
+var ErrTooLarge = errors.New("quantos decoding: value too large")
+
+// StreamDecoder reads encoded values one after the other from a
+// connection or file. Values delimit themselves, each is read whole before
+// it is decoded and never past MaxSize bytes, so a peer cannot make it
+// allocate or recurse without bound.
+type StreamDecoder struct {
+	// MaxSize limits the encoded size of a value, DefaultMaxSize when
+	// zero. MaxDepth limits nesting like Decoder.MaxDepth.
+	MaxSize  int
+	MaxDepth int
+	// Strict rejects values not in canonical form, see Validate.
+	Strict bool
+
+	r   *bufio.Reader
+	buf []byte
+}
+
+func NewStreamDecoder(r io.Reader) *StreamDecoder {
+	return &StreamDecoder{r: bufio.NewReader(r)}
+}
+
+func (s *StreamDecoder) maxSize() int {
+	if s.MaxSize > 0 {
+		return s.MaxSize
+	}
+	return DefaultMaxSize
+}
+
+func (s *StreamDecoder) maxDepth() int {
+	if s.MaxDepth > 0 {
+		return s.MaxDepth
+	}
+	return DefaultMaxDepth
+}
+
+// ReadRaw returns the encoding of the next value. It returns io.EOF when
+// the input ends between values and io.ErrUnexpectedEOF inside one.
+func (s *StreamDecoder) ReadRaw() ([]byte, error) {
+	// decoded byte strings alias the buffer, it is not reused
+	s.buf = make([]byte, 0, 512)
+	if err := s.scan(0); err != nil {
+		if err == io.EOF && len(s.buf) > 0 {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return s.buf, nil
+}
+
+// Decode reads the next value, like Decoder.Decode.
+func (s *StreamDecoder) Decode() (interface{}, error) {
+	raw, err := s.ReadRaw()
+	if err != nil {
+		return nil, err
+	}
+	d := Decoder{MaxDepth: s.MaxDepth, Strict: s.Strict}
+	return d.Decode(raw)
+}
+
+// DecodeValue reads the next value into the one v points to, like
+// Unmarshal.
+func (s *StreamDecoder) DecodeValue(v interface{}) error {
+	raw, err := s.ReadRaw()
+	if err != nil {
+		return err
+	}
+	if s.Strict {
+		d := Decoder{MaxDepth: s.MaxDepth, Strict: true}
+		if _, err := d.Decode(raw); err != nil {
+			return err
+		}
+	}
+	d := Decoder{MaxDepth: s.MaxDepth}
+	d.Reset(raw)
+	return d.DecodeValue(v)
+}
+
+func (s *StreamDecoder) readByte() (byte, error) {
+	if len(s.buf) >= s.maxSize() {
+		return 0, ErrTooLarge
+	}
+	c, err := s.r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	s.buf = append(s.buf, c)
+	return c, nil
+}
+
+// scan copies the next value to buf, checking its structure as far as
+// needed to find where it ends.
+func (s *StreamDecoder) scan(depth int) error {
+	c, err := s.readByte()
+	if err != nil {
+		return err
+	}
+	switch {
+	case c == 'i' || c == 'n':
+		for {
+			c, err := s.readByte()
+			if err != nil {
+				return err
+			}
+			if c == 'e' {
+				return nil
+			}
+			if c != '-' && (c < '0' || c > '9') {
+				return errors.New("quantos int decoder: invalid integer field")
+			}
+		}
+
+	case c == 'l' || c == 'd' || c == 'h':
+		if depth >= s.maxDepth() {
+			return ErrTooDeep
+		}
+		for {
+			next, err := s.r.Peek(1)
+			if err != nil {
+				return err
+			}
+			if next[0] == 'e' {
+				_, err := s.readByte()
+				return err
+			}
+			if err := s.scan(depth + 1); err != nil {
+				return err
+			}
+		}
+
+	case c >= '0' && c <= '9':
+		n := int(c - '0')
+		for {
+			c, err := s.readByte()
+			if err != nil {
+				return err
+			}
+			if c == ':' {
+				break
+			}
+			if c < '0' || c > '9' {
+				return errors.New("quantos bytes decoder: invalid string field")
+			}
+			n = n*10 + int(c-'0')
+			if n > s.maxSize() {
+				return ErrTooLarge
+			}
+		}
+		if n > s.maxSize()-len(s.buf) {
+			return ErrTooLarge
+		}
+		start := len(s.buf)
+		s.buf = append(s.buf, make([]byte, n)...)
+		if _, err := io.ReadFull(s.r, s.buf[start:]); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return err
+		}
+		return nil
+	}
+	return errors.New("quantos decoding: invalid value")
+}
@@ -0,0 +1,92 @@
+package decoder
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/quantosnetwork/Quantos/encoder"
+)
+
+func TestStreamRoundTrip(t *testing.T) {
+	client, server := net.Pipe()
+	values := []interface{}{
+		int64(-7),
+		[]byte("hello"),
+		[]interface{}{int64(1), []byte("x"), []interface{}{}},
+		map[string]interface{}{"a": int64(1), "b": []byte("2")},
+	}
+	go func() {
+		enc := encoder.NewStreamEncoder(client)
+		for _, v := range values {
+			if err := enc.Encode(v); err != nil {
+				t.Error(err)
+			}
+		}
+		enc.Encode(&testTx{From: "alice", Nonce: 3})
+		client.Close()
+	}()
+
+	dec := NewStreamDecoder(server)
+	for _, want := range values {
+		got, err := dec.Decode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("got %#v want %#v", got, want)
+		}
+	}
+	var tx testTx
+	if err := dec.DecodeValue(&tx); err != nil || tx.From != "alice" || tx.Nonce != 3 {
+		t.Fatalf("%v %+v", err, tx)
+	}
+	if _, err := dec.Decode(); err != io.EOF {
+		t.Fatalf("got %v at the end of the stream", err)
+	}
+}
+
+func TestStreamLimits(t *testing.T) {
+	for _, c := range []struct {
+		in  string
+		err error
+	}{
+		{"", io.EOF},
+		{"l", io.ErrUnexpectedEOF},
+		{"i12", io.ErrUnexpectedEOF},
+		{"10:short", io.ErrUnexpectedEOF},
+		{"99999999999999999999:", ErrTooLarge},
+		{"100:" + strings.Repeat("x", 100), ErrTooLarge},
+		{strings.Repeat("l", 10) + strings.Repeat("e", 10), ErrTooDeep},
+	} {
+		dec := NewStreamDecoder(strings.NewReader(c.in))
+		dec.MaxSize, dec.MaxDepth = 64, 8
+		if _, err := dec.Decode(); !errors.Is(err, c.err) {
+			t.Errorf("%.20q: got %v want %v", c.in, err, c.err)
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := encoder.NewStreamEncoder(&buf)
+	enc.MaxSize = 8
+	if err := enc.Encode("too long for the limit"); err != encoder.ErrTooLarge || buf.Len() != 0 {
+		t.Fatalf("got %v, wrote %d bytes", err, buf.Len())
+	}
+}
+
+// Malformed input must give an error, never a panic.
+func TestDecodeMalformed(t *testing.T) {
+	for _, in := range []string{
+		"", "i", "ie", "l", "d", "h", "d1:a", "d1:ai1e", "di1ei1ee",
+		"-1:", "5:abc", "9223372036854775807:", "x", ":", "l" + strings.Repeat("l", 1000),
+	} {
+		var d Decoder
+		if _, err := d.Decode([]byte(in)); err == nil {
+			t.Errorf("%.20q decoded", in)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package decoder
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/holiman/uint256"
+	"github.com/quantosnetwork/Quantos/encoder"
+	"github.com/quantosnetwork/Quantos/uint512"
+)
+
+var (
+	uint256Type = reflect.TypeOf(uint256.Int{})
+	uint512Type = reflect.TypeOf(uint512.Int{})
+	bigIntType  = reflect.TypeOf(big.Int{})
+)
+
+// Unmarshal decodes data into the value v points to, the inverse of
+// encoder.Marshal. Dictionary keys without a matching field are ignored,
+// fields without a key keep their zero value. Values implementing
+// Unmarshaler decode themselves. data must hold exactly one value.
+func Unmarshal(data []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.New("quantos decoding: Unmarshal needs a non-nil pointer")
+	}
+	if len(data) == 0 {
+		return errors.New("quantos decoding: empty input")
+	}
+	var d Decoder
+	d.Reset(data)
+	if err := d.DecodeValue(v); err != nil {
+		return err
+	}
+	return d.Finish()
+}
+
+func mismatch(src interface{}, dst reflect.Value) error {
+	return fmt.Errorf("quantos decoding: cannot decode %T into %s", src, dst.Type())
+}
+
+// integer splits a decoded integer like decodeInteger.
+func integer(src interface{}) (small int64, large *big.Int, ok bool) {
+	switch v := src.(type) {
+	case int64:
+		return v, nil, true
+	case *big.Int:
+		return 0, v, true
+	}
+	return 0, nil, false
+}
+
+func assign(dst reflect.Value, src interface{}) error {
+	switch dst.Type() {
+	case uint256Type, uint512Type, bigIntType:
+		small, large, ok := integer(src)
+		if !ok {
+			return mismatch(src, dst)
+		}
+		z := reflect.New(dst.Type())
+		var err error
+		switch z := z.Interface().(type) {
+		case *uint256.Int:
+			err = setUint256(z, small, large)
+		case *uint512.Int:
+			err = setUint512(z, small, large)
+		case *big.Int:
+			if large != nil {
+				z.Set(large)
+			} else {
+				z.SetInt64(small)
+			}
+		}
+		if err != nil {
+			return err
+		}
+		dst.Set(z.Elem())
+		return nil
+	}
+
+	switch dst.Kind() {
+	case reflect.Ptr:
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		return assign(dst.Elem(), src)
+
+	case reflect.Interface:
+		sv := reflect.ValueOf(src)
+		if !sv.Type().AssignableTo(dst.Type()) {
+			return mismatch(src, dst)
+		}
+		dst.Set(sv)
+
+	case reflect.Bool:
+		i, ok := src.(int64)
+		if !ok || i != 0 && i != 1 {
+			return mismatch(src, dst)
+		}
+		dst.SetBool(i == 1)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, ok := src.(int64)
+		if !ok || dst.OverflowInt(i) {
+			return mismatch(src, dst)
+		}
+		dst.SetInt(i)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		small, large, ok := integer(src)
+		if !ok {
+			return mismatch(src, dst)
+		}
+		if large != nil {
+			if large.Sign() < 0 || !large.IsUint64() || dst.OverflowUint(large.Uint64()) {
+				return mismatch(src, dst)
+			}
+			dst.SetUint(large.Uint64())
+			return nil
+		}
+		if small < 0 || dst.OverflowUint(uint64(small)) {
+			return mismatch(src, dst)
+		}
+		dst.SetUint(uint64(small))
+
+	case reflect.String:
+		b, ok := src.([]byte)
+		if !ok {
+			return mismatch(src, dst)
+		}
+		dst.SetString(string(b))
+
+	case reflect.Array:
+		if dst.Type().Elem().Kind() == reflect.Uint8 {
+			b, ok := src.([]byte)
+			if !ok || len(b) != dst.Len() {
+				return mismatch(src, dst)
+			}
+			reflect.Copy(dst, reflect.ValueOf(b))
+			return nil
+		}
+		list, ok := src.([]interface{})
+		if !ok || len(list) != dst.Len() {
+			return mismatch(src, dst)
+		}
+		for i, item := range list {
+			if err := assign(dst.Index(i), item); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Slice:
+		if dst.Type().Elem().Kind() == reflect.Uint8 {
+			b, ok := src.([]byte)
+			if !ok {
+				return mismatch(src, dst)
+			}
+			// the decoded bytes alias the input
+			dst.SetBytes(append([]byte(nil), b...))
+			return nil
+		}
+		list, ok := src.([]interface{})
+		if !ok {
+			return mismatch(src, dst)
+		}
+		s := reflect.MakeSlice(dst.Type(), len(list), len(list))
+		for i, item := range list {
+			if err := assign(s.Index(i), item); err != nil {
+				return err
+			}
+		}
+		dst.Set(s)
+
+	case reflect.Map:
+		if table, ok := src.(map[int]interface{}); ok {
+			return assignHashTable(dst, table)
+		}
+		dict, ok := src.(map[string]interface{})
+		if !ok || dst.Type().Key().Kind() != reflect.String {
+			return mismatch(src, dst)
+		}
+		m := reflect.MakeMapWithSize(dst.Type(), len(dict))
+		for k, item := range dict {
+			ev := reflect.New(dst.Type().Elem()).Elem()
+			if err := assign(ev, item); err != nil {
+				return err
+			}
+			m.SetMapIndex(reflect.ValueOf(k).Convert(dst.Type().Key()), ev)
+		}
+		dst.Set(m)
+
+	case reflect.Struct:
+		dict, ok := src.(map[string]interface{})
+		if !ok {
+			return mismatch(src, dst)
+		}
+		fields, err := encoder.StructFields(dst.Type())
+		if err != nil {
+			return err
+		}
+		for _, f := range fields {
+			item, ok := dict[f.Name]
+			if !ok {
+				continue
+			}
+			if err := assign(dst.FieldByIndex(f.Index), item); err != nil {
+				return fmt.Errorf("%s.%s: %w", dst.Type(), f.Name, err)
+			}
+		}
+
+	default:
+		return fmt.Errorf("quantos decoding: unsupported type: %s", dst.Type())
+	}
+	return nil
+}
+
+func assignHashTable(dst reflect.Value, table map[int]interface{}) error {
+	switch dst.Type().Key().Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+	default:
+		return mismatch(table, dst)
+	}
+	m := reflect.MakeMapWithSize(dst.Type(), len(table))
+	for k, item := range table {
+		kv := reflect.New(dst.Type().Key()).Elem()
+		if err := assign(kv, int64(k)); err != nil {
+			return err
+		}
+		ev := reflect.New(dst.Type().Elem()).Elem()
+		if err := assign(ev, item); err != nil {
+			return err
+		}
+		m.SetMapIndex(kv, ev)
+	}
+	dst.Set(m)
+	return nil
+}
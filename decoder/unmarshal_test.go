@@ -0,0 +1,107 @@
+package decoder
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/quantosnetwork/Quantos/encoder"
+)
+
+type testTx struct {
+	From   string       `quantos:"from"`
+	Amount *uint256.Int `quantos:"amount"`
+	Nonce  uint64       `quantos:"nonce"`
+	Sig    [64]byte     `quantos:"sig"`
+	Memo   []byte       `quantos:"memo,omitempty"`
+}
+
+type testBlock struct {
+	Height   uint32            `quantos:"height"`
+	Final    bool              `quantos:"final"`
+	Parent   *testBlock        `quantos:"parent"`
+	Txs      []*testTx         `quantos:"txs"`
+	Root     [2][32]byte       `quantos:"root"`
+	Meta     map[string]string `quantos:"meta"`
+	Reward   uint256.Int       `quantos:"reward"`
+	Ignored  string            `quantos:"-"`
+	Untagged int8
+	internal int
+}
+
+func TestStructRoundTrip(t *testing.T) {
+	in := &testBlock{
+		Height: 42,
+		Final:  true,
+		Parent: &testBlock{Height: 41},
+		Txs: []*testTx{
+			{From: "alice", Amount: uint256.NewInt(1000), Nonce: 7, Memo: []byte("hi")},
+			{From: "bob", Amount: new(uint256.Int).Lsh(uint256.NewInt(1), 255)},
+		},
+		Meta:     map[string]string{"b": "2", "a": "1"},
+		Reward:   *uint256.NewInt(5),
+		Ignored:  "not encoded",
+		Untagged: -3,
+		internal: 1,
+	}
+	in.Txs[0].Sig[0], in.Txs[0].Sig[63] = 1, 2
+	in.Root[1][31] = 9
+
+	b, err := encoder.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out testBlock
+	if err := Unmarshal(b, &out); err != nil {
+		t.Fatal(err)
+	}
+	in.Ignored, in.internal = "", 0
+	// an absent parent decodes to nil, the zero value of its encoding
+	if !reflect.DeepEqual(in, &out) {
+		t.Fatalf("round trip differs:\n%+v\n%+v", in, &out)
+	}
+
+	again, _ := encoder.Marshal(&out)
+	if !bytes.Equal(b, again) {
+		t.Fatal("encoding is not deterministic")
+	}
+}
+
+func TestStructTagsAndOmitEmpty(t *testing.T) {
+	b, err := encoder.Marshal(testTx{From: "a", Amount: uint256.NewInt(0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "d6:amounti0e4:from1:a5:noncei0e3:sig64:" + string(make([]byte, 64)) + "e"
+	if string(b) != want {
+		t.Fatalf("got %q", b)
+	}
+}
+
+func TestUnmarshalTypeErrors(t *testing.T) {
+	var tx testTx
+	short, _ := encoder.Marshal(map[string]interface{}{"sig": []byte("too short")})
+	if err := Unmarshal(short, &tx); err == nil {
+		t.Fatal("short [64]byte accepted")
+	}
+	neg, _ := encoder.Marshal(map[string]interface{}{"nonce": -1})
+	if err := Unmarshal(neg, &tx); err == nil {
+		t.Fatal("negative uint64 accepted")
+	}
+	var small struct{ V uint8 }
+	big, _ := encoder.Marshal(map[string]interface{}{"V": 300})
+	if err := Unmarshal(big, &small); err == nil {
+		t.Fatal("uint8 overflow accepted")
+	}
+	if err := Unmarshal(nil, &tx); err == nil {
+		t.Fatal("empty input accepted")
+	}
+	var x int
+	if err := Unmarshal([]byte("i1ei2e"), &x); err != ErrTrailing {
+		t.Fatalf("trailing bytes accepted: %v", err)
+	}
+	if err := Unmarshal(short, tx); err == nil {
+		t.Fatal("non pointer accepted")
+	}
+}
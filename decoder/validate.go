@@ -0,0 +1,41 @@
+package decoder
+
+import "errors"
+
+var (
+	ErrKeyOrder = errors.New("quantos decoding: keys not in canonical order")
+	ErrTrailing = errors.New("quantos decoding: trailing bytes")
+)
+
+/*
+
+	Canonical encoding
+
+	Signed data has exactly one encoding, the one the encoder writes:
+
+	- integers without leading zeros, no -0, n<n>e only outside the
+	  int64 range
+	- byte string lengths without leading zeros
+	- dictionary keys in increasing byte order, hashtable keys in
+	  increasing numeric order, none repeated
+	- nothing after the value
+
+	A strict Decoder and Validate reject anything else, so re-encoding what
+	they accept gives back the same bytes.
+
+*/
+
+// Validate checks that data is exactly one value in canonical form.
+func Validate(data []byte) error {
+	d := Decoder{Strict: true}
+	_, err := d.Decode(data)
+	return err
+}
+
+// UnmarshalStrict is Unmarshal for data that must be canonical.
+func UnmarshalStrict(data []byte, v interface{}) error {
+	if err := Validate(data); err != nil {
+		return err
+	}
+	return Unmarshal(data, v)
+}
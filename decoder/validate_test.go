@@ -0,0 +1,73 @@
+package decoder
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/quantosnetwork/Quantos/encoder"
+)
+
+func TestHashTableRoundTrip(t *testing.T) {
+	in := map[int]interface{}{
+		-5: []byte("minus five"),
+		3:  int64(3),
+		40: map[int]interface{}{1: []interface{}{int64(1)}},
+	}
+	b, err := encoder.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "hi-5e10:minus fivei3ei3ei40ehi1eli1eeee"; string(b) != want {
+		t.Fatalf("got %q want %q", b, want)
+	}
+	if err := Validate(b); err != nil {
+		t.Fatal(err)
+	}
+	var d Decoder
+	out, err := d.Decode(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Fatalf("got %#v", out)
+	}
+
+	typed := map[uint16]string{7: "seven", 2: "two"}
+	b, err = encoder.Marshal(typed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var back map[uint16]string
+	if err := Unmarshal(b, &back); err != nil || !reflect.DeepEqual(typed, back) {
+		t.Fatalf("%v %v", err, back)
+	}
+	var wrong map[string]string
+	if err := Unmarshal(b, &wrong); err == nil {
+		t.Fatal("hashtable decoded into a string keyed map")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for in, want := range map[string]error{
+		"d1:ai1e1:bi2ee":     nil,
+		"hi1ei1ei2ei2ee":     nil,
+		"d1:bi1e1:ai2ee":     ErrKeyOrder,
+		"d1:ai1e1:ai2ee":     ErrKeyOrder,
+		"hi2ei1ei1ei2ee":     ErrKeyOrder,
+		"hi1ei1ei1ei2ee":     ErrKeyOrder,
+		"ld1:ai1eee":         nil,
+		"li1eei1e":           ErrTrailing,
+		"lhi9e0:i-9e0:eei1e": ErrKeyOrder,
+	} {
+		err := Validate([]byte(in))
+		if want == nil && err != nil || want != nil && err != want {
+			t.Errorf("%q: got %v want %v", in, err, want)
+		}
+	}
+	if err := Validate([]byte("d1:ai1ee1:x")); err == nil {
+		t.Error("trailing string accepted")
+	}
+	if err := Validate([]byte("h1:ai1ee")); err == nil {
+		t.Error("byte string hashtable key accepted")
+	}
+}
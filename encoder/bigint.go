@@ -0,0 +1,66 @@
+package encoder
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+
+	"github.com/holiman/uint256"
+	"github.com/quantosnetwork/Quantos/uint512"
+)
+
+/*
+
+	Big integers
+
+	n<n>e               integer outside the int64 range, base 10
+
+	Unsigned 64 bit integers, uint256.Int, uint512.Int and big.Int are
+	written i<n>e when the value fits in an int64 and n<n>e otherwise, so
+	every integer has a single encoding whatever its Go type.
+
+*/
+
+func (e *Encoder) encodeBig(digits []byte) {
+	e.grow(len(digits) + 2)
+	e.writeByte('n')
+	e.write(digits)
+	e.writeByte('e')
+}
+
+// EncodeUint writes v without loss, n<n>e above math.MaxInt64.
+func (e *Encoder) EncodeUint(v uint64) {
+	if v <= math.MaxInt64 {
+		e.encodeInt(int64(v))
+		return
+	}
+	var b [20]byte
+	e.encodeBig(strconv.AppendUint(b[:0], v, 10))
+}
+
+// EncodeBigInt writes x, n<n>e outside the int64 range.
+func (e *Encoder) EncodeBigInt(x *big.Int) {
+	if x.IsInt64() {
+		e.encodeInt(x.Int64())
+		return
+	}
+	e.encodeBig(x.Append(nil, 10))
+}
+
+// EncodeUint256 writes x, n<n>e above math.MaxInt64.
+func (e *Encoder) EncodeUint256(x *uint256.Int) {
+	if x.IsUint64() {
+		e.EncodeUint(x.Uint64())
+		return
+	}
+	e.encodeBig(x.ToBig().Append(nil, 10))
+}
+
+// EncodeUint512 writes x, n<n>e above math.MaxInt64.
+func (e *Encoder) EncodeUint512(x *uint512.Int) {
+	if x.BitLen() <= 64 {
+		e.EncodeUint(x[0])
+		return
+	}
+	e.encodeBig(x.ToBig().Append(nil, 10))
+}
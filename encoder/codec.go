@@ -0,0 +1,78 @@
+package encoder
+
+import (
+	"fmt"
+
+	"github.com/quantosnetwork/Quantos/crypto"
+)
+
+// Marshaler is implemented by types that encode themselves, usually with
+// methods generated by quantosgen. The output must be what Marshal would
+// write for the value, so generated and reflective codecs can be mixed.
+type Marshaler interface {
+	MarshalQuantos(e *Encoder) error
+}
+
+// NilError is the error for a nil pointer that cannot be encoded.
+func NilError(v interface{}) error {
+	return fmt.Errorf("quantos encoding: nil %T", v)
+}
+
+// Bytes returns what has been encoded so far.
+func (e *Encoder) Bytes() []byte {
+	return e.buffer[:e.offset]
+}
+
+// Reset empties the encoder, keeping its buffer.
+func (e *Encoder) Reset() {
+	e.offset = 0
+}
+
+// Encode encodes v like EncodeTo, appending to the encoder.
+func (e *Encoder) Encode(v interface{}) error {
+	return e.encode(v)
+}
+
+func (e *Encoder) EncodeInt(v int64) {
+	e.encodeInt(v)
+}
+
+func (e *Encoder) EncodeBool(v bool) {
+	if v {
+		e.encodeInt(1)
+	} else {
+		e.encodeInt(0)
+	}
+}
+
+func (e *Encoder) EncodeBytes(b []byte) {
+	e.encodeBytes(b)
+}
+
+func (e *Encoder) EncodeString(s string) {
+	e.encodeBytes(crypto.StringToBytes(s))
+}
+
+// EncodeRaw writes b, a value encoded already, as it is.
+func (e *Encoder) EncodeRaw(b []byte) {
+	e.grow(len(b))
+	e.write(b)
+}
+
+// BeginList starts a list, End closes it.
+func (e *Encoder) BeginList() {
+	e.grow(1)
+	e.writeByte('l')
+}
+
+// BeginDict starts a dictionary, End closes it. Keys are written with
+// EncodeString in sorted order.
+func (e *Encoder) BeginDict() {
+	e.grow(1)
+	e.writeByte('d')
+}
+
+func (e *Encoder) End() {
+	e.grow(1)
+	e.writeByte('e')
+}
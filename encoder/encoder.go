@@ -1,15 +1,35 @@
 package encoder
 
 import (
+	"github.com/holiman/uint256"
 	"github.com/quantosnetwork/Quantos/crypto"
+	"github.com/quantosnetwork/Quantos/uint512"
 
 	"fmt"
-
+	"math/big"
+	"reflect"
 	"sort"
 	"sync"
 	"unsafe"
 )
 
+/*
+
+	Wire format
+
+	i<n>e               integer, base 10
+	<len>:<bytes>       byte string
+	l<value>...e        list
+	d<key><value>...e   dictionary, byte string keys in sorted order
+	h<key><value>...e   hashtable, integer keys in increasing order
+	n<n>e               big integer, see bigint.go
+
+	Dictionaries decode to map[string]interface{} and hashtables to
+	map[int]interface{}. Keys are unique, decoder.Validate rejects
+	encodings with keys out of order or repeated.
+
+*/
+
 //go:linkname memmov runtime.memmove
 func memmov(to unsafe.Pointer, from unsafe.Pointer, n uintptr)
 
@@ -98,7 +118,7 @@ func (e *Encoder) encode(data interface{}) error {
 	case int:
 		e.encodeInt(int64(value))
 	case uint64:
-		e.encodeInt(int64(value))
+		e.EncodeUint(value)
 	case uint32:
 		e.encodeInt(int64(value))
 	case uint16:
@@ -106,7 +126,26 @@ func (e *Encoder) encode(data interface{}) error {
 	case uint8:
 		e.encodeInt(int64(value))
 	case uint:
-		e.encodeInt(int64(value))
+		e.EncodeUint(uint64(value))
+	case *big.Int:
+		if value == nil {
+			return NilError(value)
+		}
+		e.EncodeBigInt(value)
+	case *uint256.Int:
+		if value == nil {
+			return NilError(value)
+		}
+		e.EncodeUint256(value)
+	case uint256.Int:
+		e.EncodeUint256(&value)
+	case *uint512.Int:
+		if value == nil {
+			return NilError(value)
+		}
+		e.EncodeUint512(value)
+	case uint512.Int:
+		e.EncodeUint512(&value)
 	case []byte:
 		e.encodeBytes(value)
 	case string:
@@ -117,8 +156,12 @@ func (e *Encoder) encode(data interface{}) error {
 		return e.encodeDictionary(value)
 	case map[int]interface{}:
 		return e.encodeHashTable(value)
-	default:
+	case Marshaler:
+		return value.MarshalQuantos(e)
+	case nil:
 		return fmt.Errorf("quantos encoding: unsupported type: %T", value)
+	default:
+		return e.encodeValue(reflect.ValueOf(value))
 	}
 	return nil
 }
@@ -199,17 +242,17 @@ func (e *Encoder) encodeDictionary(data map[string]interface{}) error {
 func (e *Encoder) encodeHashTable(data map[int]interface{}) error {
 	e.grow(1)
 	e.writeByte('h')
-	var keys []int
-	keys = make([]int, 0, len(data))
-	for key, _ := range data {
+	keys := make([]int, 0, len(data))
+	for key := range data {
 		keys = append(keys, key)
 	}
 	sort.Ints(keys)
-
-	for _, kk := range keys {
-		e.encodeInt(int64(kk))
+	for _, key := range keys {
+		e.encodeInt(int64(key))
+		if err := e.encode(data[key]); err != nil {
+			return err
+		}
 	}
-
 	e.grow(1)
 	e.writeByte('e')
 	return nil
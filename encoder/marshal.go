@@ -0,0 +1,284 @@
+package encoder
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/holiman/uint256"
+	"github.com/quantosnetwork/Quantos/crypto"
+	"github.com/quantosnetwork/Quantos/uint512"
+)
+
+/*
+
+	Struct encoding
+
+	Values the encode type switch does not know are encoded by reflection:
+
+	struct            dictionary of its exported fields, keys sorted
+	bool              i1e / i0e
+	[N]byte, []byte   byte string
+	other arrays and  list
+	slices
+	map[string]T      dictionary
+	map[int]T         hashtable, any integer key type
+	pointer           the value it points to
+	uint256.Int,      integer, see bigint.go
+	uint512.Int,
+	big.Int
+
+	Nil pointer, interface, slice and map fields are left out so they
+	decode back to nil. Values implementing Marshaler encode themselves.
+
+	Field keys come from the `quantos` tag, else the field name:
+
+	type Block struct {
+		Height uint64   `quantos:"height"`
+		Hash   [64]byte `quantos:"hash"`
+		Tx     []*Tx    `quantos:"txs,omitempty"`
+		Cache  []byte   `quantos:"-"`
+	}
+
+	omitempty leaves out zero values.
+
+*/
+
+// Field describes how a struct field is encoded.
+type Field struct {
+	Name      string
+	Index     []int
+	OmitEmpty bool
+}
+
+var fieldCache sync.Map // reflect.Type -> []Field
+
+var (
+	uint256Type   = reflect.TypeOf(uint256.Int{})
+	uint512Type   = reflect.TypeOf(uint512.Int{})
+	bigIntType    = reflect.TypeOf(big.Int{})
+	marshalerType = reflect.TypeOf((*Marshaler)(nil)).Elem()
+)
+
+// StructFields returns the encoded fields of a struct type sorted by key.
+func StructFields(t reflect.Type) ([]Field, error) {
+	if f, ok := fieldCache.Load(t); ok {
+		return f.([]Field), nil
+	}
+	var fields []Field
+	seen := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+		name := sf.Name
+		omit := false
+		if tag, ok := sf.Tag.Lookup("quantos"); ok {
+			parts := strings.Split(tag, ",")
+			if parts[0] == "-" {
+				continue
+			}
+			if parts[0] != "" {
+				name = parts[0]
+			}
+			for _, opt := range parts[1:] {
+				if opt == "omitempty" {
+					omit = true
+				}
+			}
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("quantos encoding: duplicate field key %q in %s", name, t)
+		}
+		seen[name] = true
+		fields = append(fields, Field{Name: name, Index: sf.Index, OmitEmpty: omit})
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Name < fields[j].Name })
+	fieldCache.Store(t, fields)
+	return fields, nil
+}
+
+// Marshal encodes v, structs included.
+func Marshal(v interface{}) ([]byte, error) {
+	var e Encoder
+	return e.EncodeTo(nil, v)
+}
+
+// OmitField reports whether a struct field holding v is left out of the
+// encoding.
+func OmitField(v reflect.Value, omitEmpty bool) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map:
+		if v.IsNil() {
+			return true
+		}
+	}
+	return omitEmpty && v.IsZero()
+}
+
+func (e *Encoder) encodeValue(v reflect.Value) error {
+	switch v.Type() {
+	case uint256Type:
+		u := v.Interface().(uint256.Int)
+		e.EncodeUint256(&u)
+		return nil
+	case uint512Type:
+		u := v.Interface().(uint512.Int)
+		e.EncodeUint512(&u)
+		return nil
+	case bigIntType:
+		if v.CanAddr() {
+			e.EncodeBigInt(v.Addr().Interface().(*big.Int))
+		} else {
+			b := v.Interface().(big.Int)
+			e.EncodeBigInt(&b)
+		}
+		return nil
+	}
+	if k := v.Kind(); k != reflect.Interface && (k != reflect.Ptr || !v.IsNil()) {
+		if v.Type().Implements(marshalerType) {
+			return v.Interface().(Marshaler).MarshalQuantos(e)
+		}
+		if v.CanAddr() && v.Addr().Type().Implements(marshalerType) {
+			return v.Addr().Interface().(Marshaler).MarshalQuantos(e)
+		}
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return fmt.Errorf("quantos encoding: nil %s", v.Type())
+		}
+		return e.encodeValue(v.Elem())
+	case reflect.Bool:
+		e.EncodeBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		e.encodeInt(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		e.EncodeUint(v.Uint())
+	case reflect.String:
+		e.encodeBytes(crypto.StringToBytes(v.String()))
+	case reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(b), v)
+			e.encodeBytes(b)
+			return nil
+		}
+		return e.encodeSequence(v)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			e.encodeBytes(v.Bytes())
+			return nil
+		}
+		return e.encodeSequence(v)
+	case reflect.Map:
+		return e.encodeMap(v)
+	case reflect.Struct:
+		return e.encodeStruct(v)
+	default:
+		return fmt.Errorf("quantos encoding: unsupported type: %s", v.Type())
+	}
+	return nil
+}
+
+func (e *Encoder) encodeSequence(v reflect.Value) error {
+	e.grow(1)
+	e.writeByte('l')
+	for i := 0; i < v.Len(); i++ {
+		if err := e.encodeValue(v.Index(i)); err != nil {
+			return err
+		}
+	}
+	e.grow(1)
+	e.writeByte('e')
+	return nil
+}
+
+func (e *Encoder) encodeMap(v reflect.Value) error {
+	switch v.Type().Key().Kind() {
+	case reflect.String:
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return e.encodeIntMap(v)
+	default:
+		return fmt.Errorf("quantos encoding: unsupported map key type: %s", v.Type().Key())
+	}
+	keys := make([]string, 0, v.Len())
+	for _, k := range v.MapKeys() {
+		keys = append(keys, k.String())
+	}
+	sortStrings(keys)
+	e.grow(1)
+	e.writeByte('d')
+	for _, k := range keys {
+		e.encodeBytes(crypto.StringToBytes(k))
+		if err := e.encodeValue(v.MapIndex(reflect.ValueOf(k).Convert(v.Type().Key()))); err != nil {
+			return err
+		}
+	}
+	e.grow(1)
+	e.writeByte('e')
+	return nil
+}
+
+func (e *Encoder) encodeIntMap(v reflect.Value) error {
+	type entry struct {
+		key   int64
+		value reflect.Value
+	}
+	entries := make([]entry, 0, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		k := iter.Key()
+		var key int64
+		switch k.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			key = k.Int()
+		default:
+			if k.Uint() > math.MaxInt64 {
+				return fmt.Errorf("quantos encoding: hashtable key %d overflows int64", k.Uint())
+			}
+			key = int64(k.Uint())
+		}
+		entries = append(entries, entry{key, iter.Value()})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].key < entries[j].key })
+	e.grow(1)
+	e.writeByte('h')
+	for _, en := range entries {
+		e.encodeInt(en.key)
+		if err := e.encodeValue(en.value); err != nil {
+			return err
+		}
+	}
+	e.grow(1)
+	e.writeByte('e')
+	return nil
+}
+
+func (e *Encoder) encodeStruct(v reflect.Value) error {
+	fields, err := StructFields(v.Type())
+	if err != nil {
+		return err
+	}
+	e.grow(1)
+	e.writeByte('d')
+	for _, f := range fields {
+		fv := v.FieldByIndex(f.Index)
+		if OmitField(fv, f.OmitEmpty) {
+			continue
+		}
+		e.encodeBytes(crypto.StringToBytes(f.Name))
+		if err := e.encodeValue(fv); err != nil {
+			return fmt.Errorf("%s.%s: %w", v.Type(), f.Name, err)
+		}
+	}
+	e.grow(1)
+	e.writeByte('e')
+	return nil
+}
@@ -0,0 +1,446 @@
+// Code generated by quantosgen. DO NOT EDIT.
+
+package example
+
+import (
+	"math/big"
+	"reflect"
+	"sort"
+
+	"github.com/holiman/uint256"
+	"github.com/quantosnetwork/Quantos/decoder"
+	"github.com/quantosnetwork/Quantos/encoder"
+)
+
+// MarshalQuantos encodes x like encoder.Marshal.
+func (x *Block) MarshalQuantos(e *encoder.Encoder) error {
+	if x == nil {
+		return encoder.NilError(x)
+	}
+	e.BeginDict()
+	e.EncodeString("Memo")
+	e.EncodeString(x.Memo)
+	e.EncodeString("Score")
+	e.EncodeInt(int64(x.Score))
+	if !encoder.OmitField(reflect.ValueOf(&x.Vote).Elem(), false) {
+		e.EncodeString("Vote")
+		if err := e.Encode(&x.Vote); err != nil {
+			return err
+		}
+	}
+	if !encoder.OmitField(reflect.ValueOf(&x.Any).Elem(), false) {
+		e.EncodeString("any")
+		if err := e.Encode(&x.Any); err != nil {
+			return err
+		}
+	}
+	if x.Burnt.Sign() != 0 {
+		e.EncodeString("burnt")
+		e.EncodeBigInt(&x.Burnt)
+	}
+	if x.Delta != nil {
+		e.EncodeString("delta")
+		e.EncodeBigInt(x.Delta)
+	}
+	if x.Fees != nil {
+		e.EncodeString("fees")
+		e.BeginList()
+		for i1 := range x.Fees {
+			if x.Fees[i1] == nil {
+				return encoder.NilError(x.Fees[i1])
+			}
+			e.EncodeUint256(x.Fees[i1])
+		}
+		e.End()
+	}
+	if x.Final {
+		e.EncodeString("final")
+		e.EncodeBool(x.Final)
+	}
+	if !encoder.OmitField(reflect.ValueOf(&x.Header).Elem(), false) {
+		e.EncodeString("header")
+		if err := x.Header.MarshalQuantos(e); err != nil {
+			return err
+		}
+	}
+	if x.Meta != nil {
+		e.EncodeString("meta")
+		keys2 := make([]string, 0, len(x.Meta))
+		for k3 := range x.Meta {
+			keys2 = append(keys2, string(k3))
+		}
+		sort.Strings(keys2)
+		e.BeginDict()
+		for _, k3 := range keys2 {
+			e.EncodeString(k3)
+			v4 := x.Meta[string(k3)]
+			e.EncodeString(v4)
+		}
+		e.End()
+	}
+	if x.Receipts != nil {
+		e.EncodeString("receipts")
+		keys5 := make([]string, 0, len(x.Receipts))
+		for k6 := range x.Receipts {
+			keys5 = append(keys5, string(k6))
+		}
+		sort.Strings(keys5)
+		e.BeginDict()
+		for _, k6 := range keys5 {
+			e.EncodeString(k6)
+			v7 := x.Receipts[string(k6)]
+			e.BeginList()
+			for i8 := range v7 {
+				e.EncodeBytes(v7[i8][:])
+			}
+			e.End()
+		}
+		e.End()
+	}
+	if x.Txs != nil {
+		e.EncodeString("txs")
+		e.BeginList()
+		for i9 := range x.Txs {
+			e.EncodeBytes(x.Txs[i9])
+		}
+		e.End()
+	}
+	if x.Uncle != nil {
+		e.EncodeString("uncle")
+		if err := x.Uncle.MarshalQuantos(e); err != nil {
+			return err
+		}
+	}
+	if x.Votes != nil {
+		e.EncodeString("votes")
+		e.BeginList()
+		for i10 := range x.Votes {
+			if err := e.Encode(&x.Votes[i10]); err != nil {
+				return err
+			}
+		}
+		e.End()
+	}
+	e.End()
+	return nil
+}
+
+// UnmarshalQuantos decodes x like decoder.Unmarshal.
+func (x *Block) UnmarshalQuantos(d *decoder.Decoder) error {
+	if err := d.OpenDict(); err != nil {
+		return err
+	}
+	for d.More() {
+		key, err := d.DecodeBytes()
+		if err != nil {
+			return err
+		}
+		switch string(key) {
+		case "Memo":
+			b11, err := d.DecodeBytes()
+			if err != nil {
+				return err
+			}
+			x.Memo = string(b11)
+		case "Score":
+			n12, err := d.DecodeInt(16)
+			if err != nil {
+				return err
+			}
+			x.Score = int16(n12)
+		case "Vote":
+			if err := d.DecodeValue(&x.Vote); err != nil {
+				return err
+			}
+		case "any":
+			if err := d.DecodeValue(&x.Any); err != nil {
+				return err
+			}
+		case "burnt":
+			if err := d.DecodeBigInt(&x.Burnt); err != nil {
+				return err
+			}
+		case "delta":
+			if x.Delta == nil {
+				x.Delta = new(big.Int)
+			}
+			if err := d.DecodeBigInt(x.Delta); err != nil {
+				return err
+			}
+		case "fees":
+			{
+				if err := d.OpenList(); err != nil {
+					return err
+				}
+				s13 := make([]*uint256.Int, 0)
+				for d.More() {
+					var v14 *uint256.Int
+					if v14 == nil {
+						v14 = new(uint256.Int)
+					}
+					if err := d.DecodeUint256(v14); err != nil {
+						return err
+					}
+					s13 = append(s13, v14)
+				}
+				if err := d.Close(); err != nil {
+					return err
+				}
+				x.Fees = s13
+			}
+		case "final":
+			b15, err := d.DecodeBool()
+			if err != nil {
+				return err
+			}
+			x.Final = b15
+		case "header":
+			if err := x.Header.UnmarshalQuantos(d); err != nil {
+				return err
+			}
+		case "meta":
+			{
+				if err := d.OpenDict(); err != nil {
+					return err
+				}
+				m16 := make(map[string]string)
+				for d.More() {
+					k17, err := d.DecodeBytes()
+					if err != nil {
+						return err
+					}
+					var v18 string
+					b19, err := d.DecodeBytes()
+					if err != nil {
+						return err
+					}
+					v18 = string(b19)
+					m16[string(k17)] = v18
+				}
+				if err := d.Close(); err != nil {
+					return err
+				}
+				x.Meta = m16
+			}
+		case "receipts":
+			{
+				if err := d.OpenDict(); err != nil {
+					return err
+				}
+				m20 := make(map[string][]Hash)
+				for d.More() {
+					k21, err := d.DecodeBytes()
+					if err != nil {
+						return err
+					}
+					var v22 []Hash
+					{
+						if err := d.OpenList(); err != nil {
+							return err
+						}
+						s23 := make([]Hash, 0)
+						for d.More() {
+							var v24 Hash
+							if err := d.DecodeByteArray(v24[:]); err != nil {
+								return err
+							}
+							s23 = append(s23, v24)
+						}
+						if err := d.Close(); err != nil {
+							return err
+						}
+						v22 = s23
+					}
+					m20[string(k21)] = v22
+				}
+				if err := d.Close(); err != nil {
+					return err
+				}
+				x.Receipts = m20
+			}
+		case "txs":
+			{
+				if err := d.OpenList(); err != nil {
+					return err
+				}
+				s25 := make([][]byte, 0)
+				for d.More() {
+					var v26 []byte
+					b27, err := d.DecodeBytes()
+					if err != nil {
+						return err
+					}
+					v26 = append([]byte(nil), b27...)
+					s25 = append(s25, v26)
+				}
+				if err := d.Close(); err != nil {
+					return err
+				}
+				x.Txs = s25
+			}
+		case "uncle":
+			if x.Uncle == nil {
+				x.Uncle = new(Header)
+			}
+			if err := x.Uncle.UnmarshalQuantos(d); err != nil {
+				return err
+			}
+		case "votes":
+			{
+				if err := d.OpenList(); err != nil {
+					return err
+				}
+				s28 := make([]Vote, 0)
+				for d.More() {
+					var v29 Vote
+					if err := d.DecodeValue(&v29); err != nil {
+						return err
+					}
+					s28 = append(s28, v29)
+				}
+				if err := d.Close(); err != nil {
+					return err
+				}
+				x.Votes = s28
+			}
+		default:
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		}
+	}
+	return d.Close()
+}
+
+// MarshalQuantos encodes x like encoder.Marshal.
+func (x *Header) MarshalQuantos(e *encoder.Encoder) error {
+	if x == nil {
+		return encoder.NilError(x)
+	}
+	e.BeginDict()
+	if !encoder.OmitField(reflect.ValueOf(&x.Delegates).Elem(), false) {
+		e.EncodeString("delegates")
+		e.BeginList()
+		for i30 := range x.Delegates {
+			e.EncodeString(x.Delegates[i30])
+		}
+		e.End()
+	}
+	if x.Extra != nil {
+		e.EncodeString("extra")
+		e.EncodeBytes(x.Extra)
+	}
+	e.EncodeString("height")
+	e.EncodeUint(x.Height)
+	if x.Kind != 0 {
+		e.EncodeString("kind")
+		e.EncodeInt(int64(x.Kind))
+	}
+	e.EncodeString("network")
+	e.EncodeBytes(x.Network[:])
+	e.EncodeString("parent")
+	e.EncodeBytes(x.Parent[:])
+	if !x.Reward.IsZero() {
+		e.EncodeString("reward")
+		e.EncodeUint256(&x.Reward)
+	}
+	e.EncodeString("supply")
+	e.EncodeUint(x.Supply)
+	e.EncodeString("time")
+	e.EncodeInt(x.Time)
+	if !x.Work.IsZero() {
+		e.EncodeString("work")
+		e.EncodeUint512(&x.Work)
+	}
+	e.End()
+	return nil
+}
+
+// UnmarshalQuantos decodes x like decoder.Unmarshal.
+func (x *Header) UnmarshalQuantos(d *decoder.Decoder) error {
+	if err := d.OpenDict(); err != nil {
+		return err
+	}
+	for d.More() {
+		key, err := d.DecodeBytes()
+		if err != nil {
+			return err
+		}
+		switch string(key) {
+		case "delegates":
+			{
+				if err := d.OpenList(); err != nil {
+					return err
+				}
+				i31 := 0
+				for ; d.More(); i31++ {
+					if i31 == len(x.Delegates) {
+						return decoder.ErrLength
+					}
+					b32, err := d.DecodeBytes()
+					if err != nil {
+						return err
+					}
+					x.Delegates[i31] = string(b32)
+				}
+				if i31 != len(x.Delegates) {
+					return decoder.ErrLength
+				}
+				if err := d.Close(); err != nil {
+					return err
+				}
+			}
+		case "extra":
+			b33, err := d.DecodeBytes()
+			if err != nil {
+				return err
+			}
+			x.Extra = append([]byte(nil), b33...)
+		case "height":
+			n34, err := d.DecodeUint(64)
+			if err != nil {
+				return err
+			}
+			x.Height = n34
+		case "kind":
+			n35, err := d.DecodeUint(8)
+			if err != nil {
+				return err
+			}
+			x.Kind = Kind(n35)
+		case "network":
+			if err := d.DecodeByteArray(x.Network[:]); err != nil {
+				return err
+			}
+		case "parent":
+			if err := d.DecodeByteArray(x.Parent[:]); err != nil {
+				return err
+			}
+		case "reward":
+			if err := d.DecodeUint256(&x.Reward); err != nil {
+				return err
+			}
+		case "supply":
+			n36, err := d.DecodeUint(64)
+			if err != nil {
+				return err
+			}
+			x.Supply = n36
+		case "time":
+			n37, err := d.DecodeInt(64)
+			if err != nil {
+				return err
+			}
+			x.Time = n37
+		case "work":
+			if err := d.DecodeUint512(&x.Work); err != nil {
+				return err
+			}
+		default:
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		}
+	}
+	return d.Close()
+}
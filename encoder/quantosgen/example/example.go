@@ -0,0 +1,53 @@
+// Package example shows the codecs quantosgen writes, and tests them on
+// every kind of field it handles.
+package example
+
+import (
+	"math/big"
+
+	"github.com/holiman/uint256"
+	"github.com/quantosnetwork/Quantos/sdk/config"
+	"github.com/quantosnetwork/Quantos/uint512"
+)
+
+//go:generate go run github.com/quantosnetwork/Quantos/encoder/quantosgen -type Block,Header
+
+type Kind uint8
+
+type Hash [32]byte
+
+type Header struct {
+	Height    uint64           `quantos:"height"`
+	Parent    Hash             `quantos:"parent"`
+	Time      int64            `quantos:"time"`
+	Kind      Kind             `quantos:"kind,omitempty"`
+	Network   config.NetworkID `quantos:"network"`
+	Reward    uint256.Int      `quantos:"reward,omitempty"`
+	Work      uint512.Int      `quantos:"work,omitempty"`
+	Supply    uint64           `quantos:"supply"`
+	Extra     []byte           `quantos:"extra"`
+	Delegates [2]string        `quantos:"delegates"`
+}
+
+type Vote struct {
+	Voter string `quantos:"voter"`
+	Yes   bool   `quantos:"yes"`
+}
+
+type Block struct {
+	Header Header `quantos:"header"`
+	Vote
+	Uncle    *Header           `quantos:"uncle"`
+	Txs      [][]byte          `quantos:"txs"`
+	Fees     []*uint256.Int    `quantos:"fees"`
+	Votes    []Vote            `quantos:"votes,omitempty"`
+	Meta     map[string]string `quantos:"meta"`
+	Receipts map[string][]Hash `quantos:"receipts"`
+	Final    bool              `quantos:"final,omitempty"`
+	Memo     string
+	Score    int16
+	Delta    *big.Int    `quantos:"delta"`
+	Burnt    big.Int     `quantos:"burnt,omitempty"`
+	Any      interface{} `quantos:"any"`
+	cache    []byte
+}
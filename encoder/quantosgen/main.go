@@ -0,0 +1,922 @@
+// Command quantosgen writes MarshalQuantos and UnmarshalQuantos methods for
+// struct types, so hot paths encode without reflection. The methods write
+// the same bytes as encoder.Marshal and read what decoder.Unmarshal reads.
+//
+// It is meant to run from go generate:
+//
+//	//go:generate go run github.com/quantosnetwork/Quantos/encoder/quantosgen -type Block,Header
+//
+// and writes block_quantos.go next to the package, plus block_quantos_test.go
+// checking the generated codecs against the reflective ones, with
+// benchmarks of both.
+//
+// Fields of a type from another package, or of a struct type of this
+// package not listed in -type, go through Encoder.Encode and
+// Decoder.DecodeValue, which use its own codec when it has one and
+// reflection otherwise.
+//
+// A struct embedding a type with these methods has them promoted, as with
+// encoding/json, so it needs its own.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	encoderPath = "github.com/quantosnetwork/Quantos/encoder"
+	decoderPath = "github.com/quantosnetwork/Quantos/decoder"
+	uint256Path = "github.com/holiman/uint256"
+	uint512Path = "github.com/quantosnetwork/Quantos/uint512"
+)
+
+var (
+	typeNames = flag.String("type", "", "comma separated list of struct types")
+	output    = flag.String("output", "", "output file, default <first type>_quantos.go")
+	tests     = flag.Bool("tests", true, "write round trip tests and benchmarks")
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("quantosgen: ")
+	flag.Parse()
+	if *typeNames == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+
+	pkg, err := parsePackage(dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	g := &generator{
+		dir:      dir,
+		pkg:      pkg,
+		imported: map[string]*pkgInfo{},
+		imports:  map[string]string{},
+	}
+	names := strings.Split(*typeNames, ",")
+	for _, name := range names {
+		g.types = append(g.types, strings.TrimSpace(name))
+	}
+	for _, name := range g.types {
+		if err := g.generate(name); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	out := *output
+	if out == "" {
+		out = strings.ToLower(g.types[0]) + "_quantos.go"
+	}
+	out = filepath.Join(dir, out)
+	if err := write(out, g.file()); err != nil {
+		log.Fatal(err)
+	}
+	if *tests {
+		if err := write(strings.TrimSuffix(out, ".go")+"_test.go", g.testFile()); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func write(name string, src []byte) error {
+	formatted, err := format.Source(src)
+	if err != nil {
+		return fmt.Errorf("%s: %v\n%s", name, err, src)
+	}
+	return os.WriteFile(name, formatted, 0644)
+}
+
+type decl struct {
+	spec *ast.TypeSpec
+	file *ast.File
+}
+
+type pkgInfo struct {
+	name  string
+	files []*ast.File
+	decls map[string]decl
+}
+
+func parsePackage(dir string) (*pkgInfo, error) {
+	bp, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	p := &pkgInfo{name: bp.Name, decls: map[string]decl{}}
+	for _, name := range bp.GoFiles {
+		if strings.HasSuffix(name, "_quantos.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		p.files = append(p.files, f)
+		for _, d := range f.Decls {
+			gd, ok := d.(*ast.GenDecl)
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+			for _, s := range gd.Specs {
+				ts := s.(*ast.TypeSpec)
+				p.decls[ts.Name.Name] = decl{ts, f}
+			}
+		}
+	}
+	return p, nil
+}
+
+type kind int
+
+const (
+	kInt kind = iota
+	kUint
+	kBool
+	kString
+	kBytes     // []byte
+	kByteArray // [N]byte
+	kUint256
+	kUint512
+	kBigInt // big.Int
+	kPtr
+	kSlice
+	kArray
+	kMap
+	kStruct // listed in -type, has generated methods
+	kOther  // anything else, encoded by the encoder itself
+)
+
+type typ struct {
+	kind kind
+	bits int    // kInt, kUint; 0 for int and uint
+	expr string // the type as written in the package
+	node ast.Expr
+	file *ast.File
+	elem *typ // kPtr, kSlice, kArray, kMap
+	key  *typ // kMap
+}
+
+type field struct {
+	name      string // Go field name
+	key       string // encoded key
+	omitEmpty bool
+	t         *typ
+}
+
+type generator struct {
+	dir      string
+	pkg      *pkgInfo
+	imported map[string]*pkgInfo // by import path, nil when it failed to load
+	types    []string
+	imports  map[string]string // path -> name
+	enc      bytes.Buffer
+	tmp      int
+}
+
+// owner returns the package file belongs to.
+func (g *generator) owner(file *ast.File) *pkgInfo {
+	for _, f := range g.pkg.files {
+		if f == file {
+			return g.pkg
+		}
+	}
+	for _, p := range g.imported {
+		if p == nil {
+			continue
+		}
+		for _, f := range p.files {
+			if f == file {
+				return p
+			}
+		}
+	}
+	return g.pkg
+}
+
+// load parses an imported package, to look up the types it declares.
+func (g *generator) load(path string) *pkgInfo {
+	if p, ok := g.imported[path]; ok {
+		return p
+	}
+	var p *pkgInfo
+	if bp, err := build.Import(path, g.dir, build.FindOnly); err == nil {
+		p, _ = parsePackage(bp.Dir)
+	}
+	g.imported[path] = p
+	return p
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.enc, format, args...)
+}
+
+func (g *generator) temp(prefix string) string {
+	g.tmp++
+	return prefix + strconv.Itoa(g.tmp)
+}
+
+func (g *generator) listed(name string) bool {
+	for _, t := range g.types {
+		if t == name {
+			return true
+		}
+	}
+	return false
+}
+
+var basic = map[string]*typ{
+	"int":   {kind: kInt},
+	"int8":  {kind: kInt, bits: 8},
+	"int16": {kind: kInt, bits: 16},
+	"int32": {kind: kInt, bits: 32},
+	"rune":  {kind: kInt, bits: 32},
+	"int64": {kind: kInt, bits: 64},
+	"uint":  {kind: kUint},
+	"uint8": {kind: kUint, bits: 8},
+	"byte":  {kind: kUint, bits: 8},
+	// uintptr is encoded like a 64 bit unsigned integer
+	"uintptr": {kind: kUint, bits: 64},
+	"uint16":  {kind: kUint, bits: 16},
+	"uint32":  {kind: kUint, bits: 32},
+	"uint64":  {kind: kUint, bits: 64},
+	"bool":    {kind: kBool},
+	"string":  {kind: kString},
+}
+
+// importPath returns the path file imports under name.
+func importPath(file *ast.File, name string) string {
+	for _, spec := range file.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		n := filepath.Base(path)
+		if spec.Name != nil {
+			n = spec.Name.Name
+		}
+		if n == name {
+			return path
+		}
+	}
+	return ""
+}
+
+// useImports records the packages referenced by a type written in file.
+func (g *generator) useImports(file *ast.File, expr ast.Expr) {
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if id, ok := sel.X.(*ast.Ident); ok {
+				if path := importPath(file, id.Name); path != "" {
+					g.imports[path] = id.Name
+				}
+			}
+		}
+		return true
+	})
+}
+
+// resolve works out how a field type written in file is encoded.
+func (g *generator) resolve(file *ast.File, expr ast.Expr, depth int) *typ {
+	t := g.underlying(file, expr, depth)
+	t.expr, t.node, t.file = types.ExprString(expr), expr, file
+	return t
+}
+
+func (g *generator) underlying(file *ast.File, expr ast.Expr, depth int) *typ {
+	if depth > 16 {
+		return &typ{kind: kOther}
+	}
+	switch x := expr.(type) {
+	case *ast.ParenExpr:
+		return g.underlying(file, x.X, depth)
+	case *ast.Ident:
+		if b, ok := basic[x.Name]; ok {
+			t := *b
+			return &t
+		}
+		p := g.owner(file)
+		d, ok := p.decls[x.Name]
+		if !ok {
+			return &typ{kind: kOther}
+		}
+		if _, ok := d.spec.Type.(*ast.StructType); ok {
+			if p == g.pkg && g.listed(x.Name) {
+				return &typ{kind: kStruct}
+			}
+			return &typ{kind: kOther}
+		}
+		return g.resolve(d.file, d.spec.Type, depth+1)
+	case *ast.SelectorExpr:
+		id, ok := x.X.(*ast.Ident)
+		if !ok {
+			break
+		}
+		path := importPath(file, id.Name)
+		switch {
+		case path == uint256Path && x.Sel.Name == "Int":
+			return &typ{kind: kUint256}
+		case path == uint512Path && x.Sel.Name == "Int":
+			return &typ{kind: kUint512}
+		case path == "math/big" && x.Sel.Name == "Int":
+			return &typ{kind: kBigInt}
+		}
+		// named types of other packages are only followed to basic types,
+		// the generated code cannot name what they are made of
+		p := g.load(path)
+		if p == nil {
+			break
+		}
+		if d, ok := p.decls[x.Sel.Name]; ok {
+			switch t := g.underlying(d.file, d.spec.Type, depth+1); t.kind {
+			case kInt, kUint, kBool, kString, kBytes, kByteArray, kUint256, kUint512:
+				return t
+			}
+		}
+	case *ast.StarExpr:
+		return &typ{kind: kPtr, elem: g.resolve(file, x.X, depth+1)}
+	case *ast.ArrayType:
+		elem := g.resolve(file, x.Elt, depth+1)
+		isByte := elem.kind == kUint && elem.bits == 8
+		if isByte && elem.expr != "byte" && elem.expr != "uint8" {
+			// encoded as bytes by reflection, but not a []byte
+			return &typ{kind: kOther}
+		}
+		switch {
+		case x.Len == nil && isByte:
+			return &typ{kind: kBytes}
+		case x.Len == nil:
+			return &typ{kind: kSlice, elem: elem}
+		case isByte:
+			return &typ{kind: kByteArray}
+		default:
+			return &typ{kind: kArray, elem: elem}
+		}
+	case *ast.MapType:
+		key := g.resolve(file, x.Key, depth+1)
+		if key.kind == kString {
+			return &typ{kind: kMap, key: key, elem: g.resolve(file, x.Value, depth+1)}
+		}
+	}
+	return &typ{kind: kOther}
+}
+
+// typeExpr returns the type as written, importing what it refers to.
+func (g *generator) typeExpr(t *typ) string {
+	g.useImports(t.file, t.node)
+	return t.expr
+}
+
+// fields lists the encoded fields of a struct, sorted by key like
+// encoder.StructFields.
+func (g *generator) fields(name string) ([]field, error) {
+	d, ok := g.pkg.decls[name]
+	if !ok {
+		return nil, fmt.Errorf("type %s not found", name)
+	}
+	st, ok := d.spec.Type.(*ast.StructType)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a struct type", name)
+	}
+	var fields []field
+	seen := map[string]bool{}
+	for _, f := range st.Fields.List {
+		var names []string
+		if len(f.Names) == 0 {
+			// embedded, named after its type
+			t := f.Type
+			if star, ok := t.(*ast.StarExpr); ok {
+				t = star.X
+			}
+			switch x := t.(type) {
+			case *ast.Ident:
+				names = []string{x.Name}
+			case *ast.SelectorExpr:
+				names = []string{x.Sel.Name}
+			}
+		}
+		for _, n := range f.Names {
+			names = append(names, n.Name)
+		}
+		var tag reflect.StructTag
+		if f.Tag != nil {
+			s, _ := strconv.Unquote(f.Tag.Value)
+			tag = reflect.StructTag(s)
+		}
+		for _, n := range names {
+			if !ast.IsExported(n) {
+				continue
+			}
+			fd := field{name: n, key: n}
+			if v, ok := tag.Lookup("quantos"); ok {
+				parts := strings.Split(v, ",")
+				if parts[0] == "-" {
+					continue
+				}
+				if parts[0] != "" {
+					fd.key = parts[0]
+				}
+				for _, opt := range parts[1:] {
+					if opt == "omitempty" {
+						fd.omitEmpty = true
+					}
+				}
+			}
+			if seen[fd.key] {
+				return nil, fmt.Errorf("duplicate field key %q in %s", fd.key, name)
+			}
+			seen[fd.key] = true
+			fd.t = g.resolve(d.file, f.Type, 0)
+			fields = append(fields, fd)
+		}
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].key < fields[j].key })
+	return fields, nil
+}
+
+func (g *generator) generate(name string) error {
+	fields, err := g.fields(name)
+	if err != nil {
+		return err
+	}
+
+	g.printf("// MarshalQuantos encodes x like encoder.Marshal.\n")
+	g.printf("func (x *%s) MarshalQuantos(e *encoder.Encoder) error {\n", name)
+	g.printf("if x == nil {\nreturn encoder.NilError(x)\n}\n")
+	g.printf("e.BeginDict()\n")
+	for _, f := range fields {
+		v := "x." + f.name
+		cond := g.present(v, f)
+		if cond != "" {
+			g.printf("if %s {\n", cond)
+		}
+		g.printf("e.EncodeString(%q)\n", f.key)
+		if f.t.kind == kPtr {
+			// not nil, checked above
+			g.encode("(*"+v+")", f.t.elem)
+		} else {
+			g.encode(v, f.t)
+		}
+		if cond != "" {
+			g.printf("}\n")
+		}
+	}
+	g.printf("e.End()\nreturn nil\n}\n\n")
+
+	g.printf("// UnmarshalQuantos decodes x like decoder.Unmarshal.\n")
+	g.printf("func (x *%s) UnmarshalQuantos(d *decoder.Decoder) error {\n", name)
+	g.printf("if err := d.OpenDict(); err != nil {\nreturn err\n}\n")
+	g.printf("for d.More() {\n")
+	g.printf("key, err := d.DecodeBytes()\nif err != nil {\nreturn err\n}\n")
+	g.printf("switch string(key) {\n")
+	for _, f := range fields {
+		g.printf("case %q:\n", f.key)
+		g.decode("x."+f.name, f.t)
+	}
+	g.printf("default:\nif err := d.Skip(); err != nil {\nreturn err\n}\n")
+	g.printf("}\n}\nreturn d.Close()\n}\n\n")
+	return nil
+}
+
+// present is the condition under which encoder.Marshal writes the field,
+// empty when it always does.
+func (g *generator) present(v string, f field) string {
+	switch f.t.kind {
+	case kPtr, kBytes, kSlice, kMap:
+		return v + " != nil"
+	case kOther, kStruct, kArray:
+		g.imports["reflect"] = "reflect"
+		return fmt.Sprintf("!encoder.OmitField(reflect.ValueOf(&%s).Elem(), %v)", v, f.omitEmpty)
+	}
+	if !f.omitEmpty {
+		return ""
+	}
+	switch f.t.kind {
+	case kInt, kUint:
+		return v + " != 0"
+	case kBool:
+		return v
+	case kString:
+		return v + ` != ""`
+	case kByteArray:
+		return fmt.Sprintf("%s != (%s{})", v, g.typeExpr(f.t))
+	case kUint256, kUint512:
+		return "!" + v + ".IsZero()"
+	case kBigInt:
+		return v + ".Sign() != 0"
+	}
+	panic("unreachable")
+}
+
+// pointer returns the pointer v dereferences, if it does.
+func pointer(v string) (string, bool) {
+	if strings.HasPrefix(v, "(*") && strings.HasSuffix(v, ")") {
+		return v[2 : len(v)-1], true
+	}
+	return v, false
+}
+
+func addr(v string) string {
+	if p, ok := pointer(v); ok {
+		return p
+	}
+	return "&" + v
+}
+
+// recv is v as the receiver of a method call.
+func recv(v string) string {
+	p, _ := pointer(v)
+	return p
+}
+
+// convert returns v converted to the type of t, if it is not that type
+// already.
+func (g *generator) convert(t *typ, basic, v string) string {
+	if t.expr == basic {
+		return v
+	}
+	return g.typeExpr(t) + "(" + v + ")"
+}
+
+// as returns v, a value of type t, converted to basic.
+func as(t *typ, basic, v string) string {
+	if t.expr == basic {
+		return v
+	}
+	return basic + "(" + v + ")"
+}
+
+func (g *generator) check(call string) {
+	g.printf("if err := %s; err != nil {\nreturn err\n}\n", call)
+}
+
+// encode writes the statements encoding the addressable value v.
+func (g *generator) encode(v string, t *typ) {
+	switch t.kind {
+	case kInt:
+		g.printf("e.EncodeInt(%s)\n", as(t, "int64", v))
+	case kUint:
+		if t.bits == 8 || t.bits == 16 || t.bits == 32 {
+			g.printf("e.EncodeInt(int64(%s))\n", v)
+		} else {
+			g.printf("e.EncodeUint(%s)\n", as(t, "uint64", v))
+		}
+	case kBool:
+		g.printf("e.EncodeBool(%s)\n", as(t, "bool", v))
+	case kString:
+		g.printf("e.EncodeString(%s)\n", as(t, "string", v))
+	case kBytes:
+		g.printf("e.EncodeBytes(%s)\n", v)
+	case kByteArray:
+		g.printf("e.EncodeBytes(%s[:])\n", v)
+	case kUint256:
+		g.printf("e.EncodeUint256(%s)\n", addr(v))
+	case kUint512:
+		g.printf("e.EncodeUint512(%s)\n", addr(v))
+	case kBigInt:
+		g.printf("e.EncodeBigInt(%s)\n", addr(v))
+	case kPtr:
+		g.printf("if %s == nil {\nreturn encoder.NilError(%s)\n}\n", v, v)
+		g.encode("(*"+v+")", t.elem)
+	case kSlice:
+		i := g.temp("i")
+		g.printf("e.BeginList()\nfor %s := range %s {\n", i, v)
+		g.encode(v+"["+i+"]", t.elem)
+		g.printf("}\ne.End()\n")
+	case kArray:
+		i := g.temp("i")
+		g.printf("e.BeginList()\nfor %s := range %s {\n", i, v)
+		g.encode(v+"["+i+"]", t.elem)
+		g.printf("}\ne.End()\n")
+	case kMap:
+		g.imports["sort"] = "sort"
+		keys, k, item := g.temp("keys"), g.temp("k"), g.temp("v")
+		g.printf("%s := make([]string, 0, len(%s))\n", keys, v)
+		g.printf("for %s := range %s {\n%s = append(%s, string(%s))\n}\n", k, v, keys, keys, k)
+		g.printf("sort.Strings(%s)\n", keys)
+		g.printf("e.BeginDict()\nfor _, %s := range %s {\n", k, keys)
+		g.printf("e.EncodeString(%s)\n", k)
+		g.printf("%s := %s[%s(%s)]\n", item, v, g.typeExpr(t.key), k)
+		g.encode(item, t.elem)
+		g.printf("}\ne.End()\n")
+	case kStruct:
+		g.check(recv(v) + ".MarshalQuantos(e)")
+	case kOther:
+		g.check("e.Encode(" + addr(v) + ")")
+	}
+}
+
+// decode writes the statements decoding the next value into v.
+func (g *generator) decode(v string, t *typ) {
+	switch t.kind {
+	case kInt, kUint:
+		fn := "DecodeInt"
+		if t.kind == kUint {
+			fn = "DecodeUint"
+		}
+		n := g.temp("n")
+		g.printf("%s, err := d.%s(%d)\nif err != nil {\nreturn err\n}\n", n, fn, t.bits)
+		basic := "int64"
+		if t.kind == kUint {
+			basic = "uint64"
+		}
+		g.printf("%s = %s\n", v, g.convert(t, basic, n))
+	case kBool:
+		b := g.temp("b")
+		g.printf("%s, err := d.DecodeBool()\nif err != nil {\nreturn err\n}\n", b)
+		g.printf("%s = %s\n", v, g.convert(t, "bool", b))
+	case kString, kBytes:
+		b := g.temp("b")
+		g.printf("%s, err := d.DecodeBytes()\nif err != nil {\nreturn err\n}\n", b)
+		if t.kind == kString {
+			g.printf("%s = %s(%s)\n", v, g.typeExpr(t), b)
+		} else {
+			// the decoded bytes alias the input
+			g.printf("%s = %s\n", v, g.convert(t, "[]byte", "append([]byte(nil), "+b+"...)"))
+		}
+	case kByteArray:
+		g.check("d.DecodeByteArray(" + v + "[:])")
+	case kUint256:
+		g.check("d.DecodeUint256(" + addr(v) + ")")
+	case kUint512:
+		g.check("d.DecodeUint512(" + addr(v) + ")")
+	case kBigInt:
+		g.check("d.DecodeBigInt(" + addr(v) + ")")
+	case kPtr:
+		g.printf("if %s == nil {\n%s = new(%s)\n}\n", v, v, g.typeExpr(t.elem))
+		g.decode("(*"+v+")", t.elem)
+	case kSlice:
+		s, item := g.temp("s"), g.temp("v")
+		g.printf("{\n")
+		g.check("d.OpenList()")
+		g.printf("%s := make(%s, 0)\nfor d.More() {\nvar %s %s\n", s, g.typeExpr(t), item, g.typeExpr(t.elem))
+		g.decode(item, t.elem)
+		g.printf("%s = append(%s, %s)\n}\n", s, s, item)
+		g.check("d.Close()")
+		g.printf("%s = %s\n}\n", v, s)
+	case kArray:
+		i := g.temp("i")
+		g.printf("{\n")
+		g.check("d.OpenList()")
+		g.printf("%s := 0\nfor ; d.More(); %s++ {\n", i, i)
+		g.printf("if %s == len(%s) {\nreturn decoder.ErrLength\n}\n", i, v)
+		g.decode(v+"["+i+"]", t.elem)
+		g.printf("}\nif %s != len(%s) {\nreturn decoder.ErrLength\n}\n", i, v)
+		g.check("d.Close()")
+		g.printf("}\n")
+	case kMap:
+		m, k, item := g.temp("m"), g.temp("k"), g.temp("v")
+		g.printf("{\n")
+		g.check("d.OpenDict()")
+		g.printf("%s := make(%s)\nfor d.More() {\n", m, g.typeExpr(t))
+		g.printf("%s, err := d.DecodeBytes()\nif err != nil {\nreturn err\n}\n", k)
+		g.printf("var %s %s\n", item, g.typeExpr(t.elem))
+		g.decode(item, t.elem)
+		g.printf("%s[%s(%s)] = %s\n}\n", m, g.typeExpr(t.key), k, item)
+		g.check("d.Close()")
+		g.printf("%s = %s\n}\n", v, m)
+	case kStruct:
+		g.check(recv(v) + ".UnmarshalQuantos(d)")
+	case kOther:
+		g.check("d.DecodeValue(" + addr(v) + ")")
+	}
+}
+
+func header(b *bytes.Buffer, pkg string, imports map[string]string) {
+	fmt.Fprintf(b, "// Code generated by quantosgen. DO NOT EDIT.\n\npackage %s\n\nimport (\n", pkg)
+	var std, other []string
+	for p := range imports {
+		if strings.Contains(strings.Split(p, "/")[0], ".") {
+			other = append(other, p)
+		} else {
+			std = append(std, p)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(other)
+	for i, group := range [][]string{std, other} {
+		if i > 0 && len(std) > 0 {
+			b.WriteString("\n")
+		}
+		for _, p := range group {
+			if name := imports[p]; name != filepath.Base(p) {
+				fmt.Fprintf(b, "%s %q\n", name, p)
+			} else {
+				fmt.Fprintf(b, "%q\n", p)
+			}
+		}
+	}
+	b.WriteString(")\n\n")
+}
+
+func (g *generator) file() []byte {
+	g.imports[encoderPath] = "encoder"
+	g.imports[decoderPath] = "decoder"
+	var b bytes.Buffer
+	header(&b, g.pkg.name, g.imports)
+	b.Write(g.enc.Bytes())
+	return b.Bytes()
+}
+
+func (g *generator) testFile() []byte {
+	var b bytes.Buffer
+	header(&b, g.pkg.name, map[string]string{
+		"bytes":     "bytes",
+		"math/big":  "big",
+		"math/rand": "rand",
+		"reflect":   "reflect",
+		"testing":   "testing",
+		encoderPath: "encoder",
+		decoderPath: "decoder",
+	})
+	for _, name := range g.types {
+		fmt.Fprintf(&b, testTemplate, name)
+	}
+	b.WriteString(randomFunc)
+	return b.Bytes()
+}
+
+// testTemplate compares the generated codec of a type with the reflective
+// one, which a defined type without the methods falls back to.
+const testTemplate = `
+type quantosReflect%[1]s %[1]s
+
+func TestQuantos%[1]s(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 500; i++ {
+		in := new(%[1]s)
+		quantosRandom(r, reflect.ValueOf(in).Elem(), 0)
+
+		want, wantErr := encoder.Marshal((*quantosReflect%[1]s)(in))
+		var e encoder.Encoder
+		err := in.MarshalQuantos(&e)
+		if (err == nil) != (wantErr == nil) {
+			t.Fatalf("generated error %%v, reflective error %%v", err, wantErr)
+		}
+		if err != nil {
+			continue
+		}
+		got := e.Bytes()
+		if !bytes.Equal(got, want) {
+			t.Fatalf("encodings differ:\n%%q\n%%q", got, want)
+		}
+
+		out, ref := new(%[1]s), new(%[1]s)
+		if err := decoder.Unmarshal(got, out); err != nil {
+			t.Fatal(err)
+		}
+		if err := decoder.Unmarshal(got, (*quantosReflect%[1]s)(ref)); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(out, ref) {
+			t.Fatalf("decodings differ:\n%%+v\n%%+v", out, ref)
+		}
+		again, err := encoder.Marshal(out)
+		if err != nil || !bytes.Equal(again, got) {
+			t.Fatalf("round trip differs: %%v\n%%q\n%%q", err, again, got)
+		}
+	}
+}
+
+func quantosSample%[1]s(b *testing.B) (*%[1]s, []byte) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		in := new(%[1]s)
+		quantosRandom(r, reflect.ValueOf(in).Elem(), 0)
+		if data, err := encoder.Marshal(in); err == nil {
+			return in, data
+		}
+	}
+	b.Fatal("no encodable sample")
+	return nil, nil
+}
+
+func BenchmarkQuantos%[1]sMarshal(b *testing.B) {
+	in, _ := quantosSample%[1]s(b)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var e encoder.Encoder
+		if err := in.MarshalQuantos(&e); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkQuantos%[1]sMarshalReflect(b *testing.B) {
+	in, _ := quantosSample%[1]s(b)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := encoder.Marshal((*quantosReflect%[1]s)(in)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkQuantos%[1]sUnmarshal(b *testing.B) {
+	_, data := quantosSample%[1]s(b)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := decoder.Unmarshal(data, new(%[1]s)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkQuantos%[1]sUnmarshalReflect(b *testing.B) {
+	_, data := quantosSample%[1]s(b)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := decoder.Unmarshal(data, (*quantosReflect%[1]s)(new(%[1]s))); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+`
+
+// randomFunc fills the exported fields of a value. Empty byte slices and
+// strings decode to nil, so slices are either nil or have elements.
+const randomFunc = `
+func quantosRandom(r *rand.Rand, v reflect.Value, depth int) {
+	if v.Type() == reflect.TypeOf(big.Int{}) {
+		b := make([]byte, r.Intn(80))
+		r.Read(b)
+		n := new(big.Int).SetBytes(b)
+		if r.Intn(2) == 0 {
+			n.Neg(n)
+		}
+		v.Set(reflect.ValueOf(n).Elem())
+		return
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		v.SetBool(r.Intn(2) == 1)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n := r.Int63() >> uint(r.Intn(64))
+		if r.Intn(2) == 0 {
+			n = -n
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		v.SetUint(r.Uint64() >> uint(r.Intn(64)))
+	case reflect.String:
+		b := make([]byte, r.Intn(8))
+		r.Read(b)
+		v.SetString(string(b))
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			quantosRandom(r, v.Index(i), depth)
+		}
+	case reflect.Slice:
+		if depth > 3 || r.Intn(4) == 0 {
+			return
+		}
+		n := 1 + r.Intn(3)
+		v.Set(reflect.MakeSlice(v.Type(), n, n))
+		for i := 0; i < n; i++ {
+			quantosRandom(r, v.Index(i), depth+1)
+		}
+	case reflect.Map:
+		if depth > 3 || r.Intn(4) == 0 {
+			return
+		}
+		v.Set(reflect.MakeMap(v.Type()))
+		for i := 1 + r.Intn(3); i > 0; i-- {
+			k := reflect.New(v.Type().Key()).Elem()
+			e := reflect.New(v.Type().Elem()).Elem()
+			quantosRandom(r, k, depth+1)
+			quantosRandom(r, e, depth+1)
+			v.SetMapIndex(k, e)
+		}
+	case reflect.Ptr:
+		if depth > 3 || r.Intn(4) == 0 {
+			return
+		}
+		v.Set(reflect.New(v.Type().Elem()))
+		quantosRandom(r, v.Elem(), depth+1)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath == "" {
+				quantosRandom(r, v.Field(i), depth)
+			}
+		}
+	}
+}
+`
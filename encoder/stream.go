@@ -0,0 +1,38 @@
+package encoder
+
+import (
+	"errors"
+	"io"
+)
+
+var ErrTooLarge = errors.New("quantos encoding: value too large")
+
+// StreamEncoder writes encoded values one after the other to a connection
+// or file, the counterpart of decoder.StreamDecoder. Each value is
+// encoded in a buffer reused between calls and written with one Write.
+type StreamEncoder struct {
+	// MaxSize limits the encoded size of a value, unlimited when zero.
+	// Set it to the MaxSize of the reading side.
+	MaxSize int
+
+	w io.Writer
+	e Encoder
+}
+
+func NewStreamEncoder(w io.Writer) *StreamEncoder {
+	return &StreamEncoder{w: w}
+}
+
+// Encode writes v, or nothing when it cannot be encoded or is too large.
+func (s *StreamEncoder) Encode(v interface{}) error {
+	s.e.Reset()
+	if err := s.e.Encode(v); err != nil {
+		return err
+	}
+	b := s.e.Bytes()
+	if s.MaxSize > 0 && len(b) > s.MaxSize {
+		return ErrTooLarge
+	}
+	_, err := s.w.Write(b)
+	return err
+}
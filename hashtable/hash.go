@@ -0,0 +1,131 @@
+package hashtable
+
+import (
+	"encoding/binary"
+	"math"
+	"reflect"
+	"sync"
+
+	"github.com/dchest/siphash"
+	"github.com/zeebo/blake3"
+	"lukechampine.com/frand"
+)
+
+// HashFunc hashes the bytes of a key. Tables use a keyed one, so peers
+// sending keys can not pick ones that all land in the same bucket.
+type HashFunc func(b []byte) uint64
+
+// NewSipHash returns SipHash-2-4 under a random key, the default.
+func NewSipHash() HashFunc {
+	var key [16]byte
+	frand.Read(key[:])
+	return SipHashWithKey(binary.LittleEndian.Uint64(key[:8]), binary.LittleEndian.Uint64(key[8:]))
+}
+
+// SipHashWithKey returns SipHash-2-4 under the key k0, k1.
+func SipHashWithKey(k0, k1 uint64) HashFunc {
+	return func(b []byte) uint64 {
+		return siphash.Hash(k0, k1, b)
+	}
+}
+
+// NewBlake3 returns keyed BLAKE3 under a random key.
+func NewBlake3() HashFunc {
+	var key [32]byte
+	frand.Read(key[:])
+	return Blake3WithKey(key)
+}
+
+// Blake3WithKey returns keyed BLAKE3 under key, truncated to 64 bits.
+func Blake3WithKey(key [32]byte) HashFunc {
+	pool := sync.Pool{New: func() interface{} {
+		h, _ := blake3.NewKeyed(key[:])
+		return h
+	}}
+	return func(b []byte) uint64 {
+		h := pool.Get().(*blake3.Hasher)
+		h.Reset()
+		h.Write(b)
+		var sum [8]byte
+		h.Digest().Read(sum[:])
+		pool.Put(h)
+		return binary.LittleEndian.Uint64(sum[:])
+	}
+}
+
+// appendKey appends bytes identifying k to b. Equal keys give equal
+// bytes, which is all hashing needs, the table compares the keys
+// themselves.
+func appendKey[K comparable](b []byte, key K) []byte {
+	switch k := interface{}(key).(type) {
+	case string:
+		return append(b, k...)
+	case int:
+		return appendUint64(b, uint64(k))
+	case int64:
+		return appendUint64(b, uint64(k))
+	case uint64:
+		return appendUint64(b, k)
+	case [32]byte:
+		return append(b, k[:]...)
+	case [64]byte:
+		return append(b, k[:]...)
+	}
+	return appendValue(b, reflect.ValueOf(key))
+}
+
+func appendValue(b []byte, v reflect.Value) []byte {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return append(b, 0)
+	case reflect.Bool:
+		if v.Bool() {
+			return append(b, 1)
+		}
+		return append(b, 0)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return appendUint64(b, uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return appendUint64(b, v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return appendFloat(b, v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		return appendFloat(appendFloat(b, real(v.Complex())), imag(v.Complex()))
+	case reflect.String:
+		b = appendUint64(b, uint64(v.Len()))
+		return append(b, v.String()...)
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			b = appendValue(b, v.Index(i))
+		}
+		return b
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			b = appendValue(b, v.Field(i))
+		}
+		return b
+	case reflect.Interface:
+		if v.IsNil() {
+			return append(b, 0)
+		}
+		b = append(b, v.Elem().Type().String()...)
+		return appendValue(b, v.Elem())
+	case reflect.Ptr, reflect.Chan, reflect.UnsafePointer:
+		return appendUint64(b, uint64(v.Pointer()))
+	}
+	panic("hashtable: unhashable key type " + v.Type().String())
+}
+
+// appendFloat writes 0 for -0, which equals it.
+func appendFloat(b []byte, f float64) []byte {
+	if f == 0 {
+		f = 0
+	}
+	return appendUint64(b, math.Float64bits(f))
+}
+
+func appendUint64(b []byte, v uint64) []byte {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	return append(b, buf[:]...)
+}
@@ -1,72 +1,306 @@
 package hashtable
 
 import (
-	"fmt"
-	"github.com/davecgh/go-spew/spew"
-	"github.com/quantosnetwork/Quantos/protocol"
+	"bytes"
+	"errors"
+	"reflect"
+	"sort"
 	"sync"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/quantosnetwork/Quantos/decoder"
+	"github.com/quantosnetwork/Quantos/encoder"
 )
 
-type Key interface{}
-type Value interface{}
+const (
+	minBuckets = 8
+	// maxLoad is the number of entries per bucket that makes a table grow.
+	maxLoad = 4
+)
 
-type HashTable struct {
-	items map[int]Value
-	lock  sync.RWMutex
+type entry[K comparable, V any] struct {
+	hash  uint64
+	key   K
+	value V
 }
 
-func _hash(k Key) int {
-	key := fmt.Sprintf("%s", k)
-	h := 0
-	for i := 0; i < len(key); i++ {
-		h = 31*h + int(key[i])
-	}
+// HashTable maps keys to values. Keys are hashed with a HashFunc, SipHash
+// under a random key unless New is given another, and kept next to their
+// values in the bucket of their hash, so colliding keys live side by side.
+// The zero value is an empty table ready to use.
+type HashTable[K comparable, V any] struct {
+	lock    sync.RWMutex
+	hash    HashFunc
+	buckets [][]entry[K, V]
+	size    int
+}
+
+// New returns an empty table hashing keys with hash, NewSipHash() when it
+// is nil.
+func New[K comparable, V any](hash HashFunc) *HashTable[K, V] {
+	return &HashTable[K, V]{hash: hash}
+}
+
+var keyBuffers = sync.Pool{New: func() interface{} { return new([]byte) }}
+
+func (ht *HashTable[K, V]) hashKey(k K) uint64 {
+	buf := keyBuffers.Get().(*[]byte)
+	*buf = appendKey((*buf)[:0], k)
+	h := ht.hash(*buf)
+	keyBuffers.Put(buf)
 	return h
 }
 
-func (ht *HashTable) Put(k Key, v Value) {
+// find returns the bucket of hash and the index of k in it, -1 if absent.
+func (ht *HashTable[K, V]) find(hash uint64, k K) (int, int) {
+	if len(ht.buckets) == 0 {
+		return -1, -1
+	}
+	b := int(hash & uint64(len(ht.buckets)-1))
+	for i, e := range ht.buckets[b] {
+		if e.hash == hash && e.key == k {
+			return b, i
+		}
+	}
+	return b, -1
+}
+
+func (ht *HashTable[K, V]) Put(k K, v V) {
 	ht.lock.Lock()
 	defer ht.lock.Unlock()
-	i := _hash(k)
-	if ht.items == nil {
-		ht.items = make(map[int]Value)
+	if ht.hash == nil {
+		ht.hash = NewSipHash()
 	}
-	ht.items[i] = v
+	h := ht.hashKey(k)
+	if b, i := ht.find(h, k); i >= 0 {
+		ht.buckets[b][i].value = v
+		return
+	}
+	if ht.size >= maxLoad*len(ht.buckets) {
+		ht.grow()
+	}
+	b := int(h & uint64(len(ht.buckets)-1))
+	ht.buckets[b] = append(ht.buckets[b], entry[K, V]{h, k, v})
+	ht.size++
+}
+
+// grow doubles the buckets, their count stays a power of two.
+func (ht *HashTable[K, V]) grow() {
+	n := 2 * len(ht.buckets)
+	if n < minBuckets {
+		n = minBuckets
+	}
+	buckets := make([][]entry[K, V], n)
+	for _, bucket := range ht.buckets {
+		for _, e := range bucket {
+			b := int(e.hash & uint64(n-1))
+			buckets[b] = append(buckets[b], e)
+		}
+	}
+	ht.buckets = buckets
+}
 
+// Get returns the value of k, the zero value if there is none.
+func (ht *HashTable[K, V]) Get(k K) V {
+	v, _ := ht.GetOK(k)
+	return v
 }
 
-func (ht *HashTable) Get(k Key) Value {
+// GetOK returns the value of k and whether there is one.
+func (ht *HashTable[K, V]) GetOK(k K) (V, bool) {
 	ht.lock.RLock()
 	defer ht.lock.RUnlock()
-	i := _hash(k)
-	return ht.items[i]
+	var zero V
+	if ht.size == 0 {
+		return zero, false
+	}
+	b, i := ht.find(ht.hashKey(k), k)
+	if i < 0 {
+		return zero, false
+	}
+	return ht.buckets[b][i].value, true
 }
 
-func (ht *HashTable) Remove(k Key) {
+// Remove deletes k and reports whether it was there.
+func (ht *HashTable[K, V]) Remove(k K) bool {
 	ht.lock.Lock()
 	defer ht.lock.Unlock()
-	i := _hash(k)
-	delete(ht.items, i)
+	if ht.size == 0 {
+		return false
+	}
+	b, i := ht.find(ht.hashKey(k), k)
+	if i < 0 {
+		return false
+	}
+	bucket := ht.buckets[b]
+	last := len(bucket) - 1
+	bucket[i] = bucket[last]
+	bucket[last] = entry[K, V]{}
+	ht.buckets[b] = bucket[:last]
+	ht.size--
+	return true
 }
 
-func (ht *HashTable) Size() int {
+func (ht *HashTable[K, V]) Size() int {
 	ht.lock.RLock()
 	defer ht.lock.RUnlock()
-	return len(ht.items)
+	return ht.size
 }
 
-func (ht *HashTable) PrintHashTable() {
-	spew.Dump(ht)
+// Range calls f for every entry until it returns false, in no particular
+// order. The table is read locked meanwhile, f must not change it.
+func (ht *HashTable[K, V]) Range(f func(k K, v V) bool) {
+	ht.lock.RLock()
+	defer ht.lock.RUnlock()
+	for _, bucket := range ht.buckets {
+		for _, e := range bucket {
+			if !f(e.key, e.value) {
+				return
+			}
+		}
+	}
 }
 
-func (ht *HashTable) ToBytes() ([]byte, error) {
-	return protocol.Marshal(ht.Items())
+func (ht *HashTable[K, V]) Keys() []K {
+	keys := make([]K, 0, ht.Size())
+	ht.Range(func(k K, _ V) bool {
+		keys = append(keys, k)
+		return true
+	})
+	return keys
 }
 
-func (ht *HashTable) Items() map[int]interface{} {
-	I := make(map[int]interface{}, ht.Size())
-	for k, v := range ht.items {
-		I[k] = v
+// Items copies the table into a map.
+func (ht *HashTable[K, V]) Items() map[K]V {
+	items := make(map[K]V, ht.Size())
+	ht.Range(func(k K, v V) bool {
+		items[k] = v
+		return true
+	})
+	return items
+}
+
+func (ht *HashTable[K, V]) PrintHashTable() {
+	spew.Dump(ht.Items())
+}
+
+/*
+
+	Encoding
+
+	Tables with integer keys keep the hashtable encoding, the keys sorted
+	and within the int64 range:
+
+	h <key><value> <key><value> … e
+
+	Other tables encode as a list of [key, value] pairs, sorted by the
+	encoding of their key so equal tables encode the same whatever their
+	hash function:
+
+	l l<key><value>e l<key><value>e … e
+
+*/
+
+// integerKeys reports whether K is an integer type, encoded as h.
+func integerKeys[K comparable]() bool {
+	switch reflect.TypeOf((*K)(nil)).Elem().Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return false
+}
+
+// ToBytes encodes the entries, keys and values with the encoder.
+func (ht *HashTable[K, V]) ToBytes() ([]byte, error) {
+	if integerKeys[K]() {
+		return encoder.Marshal(ht.Items())
+	}
+	type pair struct{ key, value []byte }
+	var (
+		pairs []pair
+		err   error
+	)
+	ht.Range(func(k K, v V) bool {
+		var p pair
+		if p.key, err = encoder.Marshal(k); err != nil {
+			return false
+		}
+		if p.value, err = encoder.Marshal(v); err != nil {
+			return false
+		}
+		pairs = append(pairs, p)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(pairs, func(i, j int) bool { return bytes.Compare(pairs[i].key, pairs[j].key) < 0 })
+	var e encoder.Encoder
+	e.BeginList()
+	for _, p := range pairs {
+		e.BeginList()
+		e.EncodeRaw(p.key)
+		e.EncodeRaw(p.value)
+		e.End()
+	}
+	e.End()
+	return e.Bytes(), nil
+}
+
+// FromBytes replaces the entries with those encoded by ToBytes.
+func (ht *HashTable[K, V]) FromBytes(b []byte) error {
+	ht.lock.RLock()
+	table := New[K, V](ht.hash)
+	ht.lock.RUnlock()
+	if integerKeys[K]() {
+		var items map[K]V
+		// strict, so a repeated key is an error rather than overwriting
+		if err := decoder.UnmarshalStrict(b, &items); err != nil {
+			return err
+		}
+		for k, v := range items {
+			table.Put(k, v)
+		}
+	} else if err := decodePairs(b, table); err != nil {
+		return err
+	}
+	ht.lock.Lock()
+	defer ht.lock.Unlock()
+	ht.hash, ht.buckets, ht.size = table.hash, table.buckets, table.size
+	return nil
+}
+
+func decodePairs[K comparable, V any](b []byte, table *HashTable[K, V]) error {
+	var d decoder.Decoder
+	d.Reset(b)
+	if err := d.OpenList(); err != nil {
+		return err
+	}
+	for d.More() {
+		var (
+			k K
+			v V
+		)
+		if err := d.OpenList(); err != nil {
+			return err
+		}
+		if err := d.DecodeValue(&k); err != nil {
+			return err
+		}
+		if err := d.DecodeValue(&v); err != nil {
+			return err
+		}
+		if err := d.Close(); err != nil {
+			return err
+		}
+		if _, ok := table.GetOK(k); ok {
+			return errors.New("quantos hashtable: duplicate key")
+		}
+		table.Put(k, v)
+	}
+	if err := d.Close(); err != nil {
+		return err
 	}
-	return I
+	return d.Finish()
 }
@@ -1,34 +1,39 @@
 package hashtable
 
 import (
+	"bytes"
 	"crypto/sha256"
+	"fmt"
 	"github.com/davecgh/go-spew/spew"
 	"log"
+	"math"
 	"math/rand"
+	"reflect"
 	"testing"
 	"time"
 )
 
-
-
 func TestHashTable_Put(t *testing.T) {
-	htable := &HashTable{}
+	htable := &HashTable[string, []byte]{}
 	contentKey, contentVal := generateHashtableContent(10)
 	for i, c := range contentKey {
 		htable.Put(c, contentVal[i])
 	}
 
-
 	//htable.PrintHashTable()
 
-	/*if htable.Size() < 10 {
-		log.Fatalf("hashtable should have 10 records it only has: %v", htable.Size())
-	}*/
-
+	if htable.Size() != 10 {
+		t.Fatalf("hashtable should have 10 records it has: %v", htable.Size())
+	}
+	for i, c := range contentKey {
+		if v, ok := htable.GetOK(c); !ok || !bytes.Equal(v, contentVal[i]) {
+			t.Fatalf("record %d lost", i)
+		}
+	}
 }
 
-func createMockHashtable(size int) *HashTable {
-	htable := &HashTable{}
+func createMockHashtable(size int) *HashTable[string, []byte] {
+	htable := &HashTable[string, []byte]{}
 	contentKey, contentVal := generateHashtableContent(size)
 	for i, c := range contentKey {
 		htable.Put(c, contentVal[i])
@@ -54,14 +59,14 @@ func randomBytes() []byte {
 	return buf
 }
 
-func generateHashtableContent(size int) ([]Key, []Value) {
+func generateHashtableContent(size int) ([]string, [][]byte) {
 
-	k := make([]Key, size)
-	v := make([]Value, size)
+	k := make([]string, size)
+	v := make([][]byte, size)
 
 	for i := 0; i < size; i++ {
-		k[i] = Key(randomBytes())
-		v[i] = Value(generateRandomHash(randomBytes()))
+		k[i] = string(randomBytes())
+		v[i] = generateRandomHash(randomBytes())
 	}
 	return k, v
 
@@ -71,4 +76,165 @@ func generateRandomHash(src []byte) []byte {
 	hasher := sha256.New()
 	hasher.Write(src)
 	return hasher.Sum(nil)
-}
\ No newline at end of file
+}
+func TestHashTable_FromBytes(t *testing.T) {
+	ht := createMockHashtable(100)
+	b, err := ht.ToBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded := New[string, []byte](NewBlake3())
+	loaded.Put("gone", nil)
+	if err := loaded.FromBytes(b); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ht.Items(), loaded.Items()) {
+		t.Fatal("hashtable does not round trip")
+	}
+	again, _ := loaded.ToBytes()
+	if !bytes.Equal(b, again) {
+		t.Fatal("encoding is not deterministic")
+	}
+	if err := loaded.FromBytes([]byte("ll1:a1:bel1:a1:cee")); err == nil {
+		t.Fatal("duplicate key loaded")
+	}
+}
+
+func TestHashTable_Collisions(t *testing.T) {
+	ht := New[int, string](func([]byte) uint64 { return 42 })
+	for i := 0; i < 100; i++ {
+		ht.Put(i, fmt.Sprint(i))
+	}
+	ht.Put(7, "seven")
+	for i := 0; i < 100; i += 2 {
+		if !ht.Remove(i) {
+			t.Fatalf("%d not removed", i)
+		}
+	}
+	if ht.Remove(0) || ht.Size() != 50 {
+		t.Fatalf("size %d after removals", ht.Size())
+	}
+	for i := 0; i < 100; i++ {
+		v, ok := ht.GetOK(i)
+		switch {
+		case i%2 == 0 && ok:
+			t.Fatalf("%d still there", i)
+		case i == 7 && v != "seven", i%2 == 1 && i != 7 && v != fmt.Sprint(i):
+			t.Fatalf("%d holds %q", i, v)
+		}
+	}
+}
+
+func TestHashTable_Range(t *testing.T) {
+	ht := New[string, int](SipHashWithKey(1, 2))
+	want := map[string]int{}
+	for i := 0; i < 1000; i++ {
+		k := fmt.Sprintf("key %d", i)
+		ht.Put(k, i)
+		want[k] = i
+	}
+	if !reflect.DeepEqual(ht.Items(), want) || len(ht.Keys()) != 1000 {
+		t.Fatal("items differ")
+	}
+	n := 0
+	ht.Range(func(string, int) bool {
+		n++
+		return n < 10
+	})
+	if n != 10 {
+		t.Fatalf("Range went on to %d", n)
+	}
+	var empty HashTable[string, int]
+	if v, ok := empty.GetOK("a"); ok || v != 0 || empty.Remove("a") || empty.Get("a") != 0 {
+		t.Fatal("zero table not empty")
+	}
+}
+
+func TestHashTable_Keys(t *testing.T) {
+	type point struct {
+		X, Y float64
+		Name string
+	}
+	ht := &HashTable[point, int]{}
+	ht.Put(point{0, 1, "a"}, 1)
+	if v, ok := ht.GetOK(point{math.Copysign(0, -1), 1, "a"}); !ok || v != 1 {
+		t.Fatal("-0 and 0 are different keys")
+	}
+	if _, ok := ht.GetOK(point{0, 1, "b"}); ok {
+		t.Fatal("different key found")
+	}
+
+}
+
+func TestHashFunc(t *testing.T) {
+	key := [32]byte{1}
+	b1, b2 := Blake3WithKey(key), Blake3WithKey(key)
+	if b1([]byte("a")) != b2([]byte("a")) || b1([]byte("a")) == b1([]byte("b")) {
+		t.Fatal("blake3 hash is not a function of its input")
+	}
+	if NewBlake3()([]byte("a")) == NewBlake3()([]byte("a")) {
+		t.Fatal("blake3 keys are not random")
+	}
+	if NewSipHash()([]byte("a")) == NewSipHash()([]byte("a")) {
+		t.Fatal("siphash keys are not random")
+	}
+	if SipHashWithKey(1, 2)(nil) != SipHashWithKey(1, 2)(nil) {
+		t.Fatal("siphash is not a function of its input")
+	}
+}
+
+func benchmarkGet(b *testing.B, hash HashFunc) {
+	ht := New[string, int](hash)
+	keys := make([]string, 1024)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("qbit1%040d", i)
+		ht.Put(keys[i], i)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ht.Get(keys[i%len(keys)])
+	}
+}
+
+func BenchmarkHashTable_GetSipHash(b *testing.B) { benchmarkGet(b, NewSipHash()) }
+func BenchmarkHashTable_GetBlake3(b *testing.B)  { benchmarkGet(b, NewBlake3()) }
+
+func TestHashTable_IntegerKeys(t *testing.T) {
+	ht := New[int, string](nil)
+	ht.Put(7, "seven")
+	ht.Put(-1, "minus one")
+	ht.Put(42, "")
+	b, err := ht.ToBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "hi-1e9:minus onei7e5:seveni42e0:e"
+	if string(b) != want {
+		t.Fatalf("encoded %q, want %q", b, want)
+	}
+	// the same bytes a map[int]string marshals to
+	var loaded HashTable[int64, string]
+	if err := loaded.FromBytes([]byte(want)); err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Size() != 3 || loaded.Get(-1) != "minus one" || loaded.Get(7) != "seven" {
+		t.Fatal("integer keyed table does not round trip")
+	}
+	if err := loaded.FromBytes([]byte("ll1:a1:bee")); err == nil {
+		t.Fatal("list of pairs loaded into an integer keyed table")
+	}
+	if err := loaded.FromBytes([]byte("hi1e1:ai1e1:be")); err == nil {
+		t.Fatal("duplicate integer key loaded")
+	}
+	if err := loaded.FromBytes([]byte(want + "JUNK")); err == nil {
+		t.Fatal("trailing bytes loaded")
+	}
+	var pairs HashTable[string, string]
+	if err := pairs.FromBytes([]byte("ll1:a1:beeJUNK")); err == nil {
+		t.Fatal("trailing bytes after the pairs loaded")
+	}
+	if loaded.Size() != 3 || pairs.Size() != 0 {
+		t.Fatal("a failed load changed the table")
+	}
+}
@@ -0,0 +1,302 @@
+package hashtable
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+/*
+
+	Sharded tables
+
+	A ShardedTable spreads its keys over shards by the high bits of their
+	hash, each shard with its own lock, so writers to different shards do
+	not wait on each other. Inside a shard keys go to buckets by the low
+	bits of their hash.
+
+	Buckets are never changed once published: a writer builds the new
+	bucket and swaps the pointer to it. Readers load the pointers without
+	locking and see a bucket either before or after a write, never in
+	between.
+
+	Snapshot marks the bucket arrays of every shard as frozen, taking all
+	shard locks so no write is half done across shards. The next writer
+	to a shard copies its bucket array, the pointers only, before writing,
+	which leaves the snapshot as it was.
+
+*/
+
+type shard[K comparable, V any] struct {
+	size  int64 // first, 64 bit atomics need 8 byte alignment
+	mu    sync.Mutex
+	table unsafe.Pointer // *shardTable[K, V]
+	// keep shards on their own cache lines
+	_ [40]byte
+}
+
+type shardTable[K comparable, V any] struct {
+	buckets []unsafe.Pointer // *[]entry[K, V], nil when empty
+	frozen  bool             // held by a snapshot, only read under the shard lock
+}
+
+// ShardedTable is a concurrent HashTable for read heavy use. Reads take no
+// lock, writes lock one shard. Use NewSharded to create one.
+type ShardedTable[K comparable, V any] struct {
+	hash   HashFunc
+	shards []shard[K, V]
+	shift  uint // hash >> shift is the shard, 64 for a single one
+}
+
+// NewSharded returns an empty table of n shards, rounded up to a power of
+// two, hashing keys with hash. n <= 0 picks 4 shards per CPU, a nil hash
+// NewSipHash().
+func NewSharded[K comparable, V any](n int, hash HashFunc) *ShardedTable[K, V] {
+	if n <= 0 {
+		n = 4 * runtime.GOMAXPROCS(0)
+	}
+	bits := uint(0)
+	for 1<<bits < n {
+		bits++
+	}
+	if hash == nil {
+		hash = NewSipHash()
+	}
+	m := &ShardedTable[K, V]{
+		hash:   hash,
+		shards: make([]shard[K, V], 1<<bits),
+		shift:  64 - bits,
+	}
+	for i := range m.shards {
+		m.shards[i].table = unsafe.Pointer(&shardTable[K, V]{buckets: make([]unsafe.Pointer, minBuckets)})
+	}
+	return m
+}
+
+func (m *ShardedTable[K, V]) hashKey(k K) uint64 {
+	buf := keyBuffers.Get().(*[]byte)
+	*buf = appendKey((*buf)[:0], k)
+	h := m.hash(*buf)
+	keyBuffers.Put(buf)
+	return h
+}
+
+func (m *ShardedTable[K, V]) shardOf(h uint64) *shard[K, V] {
+	return &m.shards[h>>m.shift]
+}
+
+func (s *shard[K, V]) load() *shardTable[K, V] {
+	return (*shardTable[K, V])(atomic.LoadPointer(&s.table))
+}
+
+func (t *shardTable[K, V]) bucket(h uint64) *unsafe.Pointer {
+	return &t.buckets[h&uint64(len(t.buckets)-1)]
+}
+
+func loadBucket[K comparable, V any](p *unsafe.Pointer) []entry[K, V] {
+	b := (*[]entry[K, V])(atomic.LoadPointer(p))
+	if b == nil {
+		return nil
+	}
+	return *b
+}
+
+func lookup[K comparable, V any](t *shardTable[K, V], h uint64, k K) (V, bool) {
+	for _, e := range loadBucket[K, V](t.bucket(h)) {
+		if e.hash == h && e.key == k {
+			return e.value, true
+		}
+	}
+	var zero V
+	return zero, false
+}
+
+// Get returns the value of k, the zero value if there is none.
+func (m *ShardedTable[K, V]) Get(k K) V {
+	v, _ := m.GetOK(k)
+	return v
+}
+
+// GetOK returns the value of k and whether there is one.
+func (m *ShardedTable[K, V]) GetOK(k K) (V, bool) {
+	h := m.hashKey(k)
+	return lookup(m.shardOf(h).load(), h, k)
+}
+
+// writable returns the table of s writers may change, copying it first if
+// a snapshot holds it. s must be locked.
+func (s *shard[K, V]) writable() *shardTable[K, V] {
+	t := s.load()
+	if !t.frozen {
+		return t
+	}
+	c := &shardTable[K, V]{buckets: make([]unsafe.Pointer, len(t.buckets))}
+	copy(c.buckets, t.buckets)
+	atomic.StorePointer(&s.table, unsafe.Pointer(c))
+	return c
+}
+
+func (m *ShardedTable[K, V]) Put(k K, v V) {
+	h := m.hashKey(k)
+	s := m.shardOf(h)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := s.writable()
+	p := t.bucket(h)
+	old := loadBucket[K, V](p)
+	for i, e := range old {
+		if e.hash == h && e.key == k {
+			b := make([]entry[K, V], len(old))
+			copy(b, old)
+			b[i].value = v
+			atomic.StorePointer(p, unsafe.Pointer(&b))
+			return
+		}
+	}
+	b := make([]entry[K, V], len(old)+1)
+	copy(b, old)
+	b[len(old)] = entry[K, V]{h, k, v}
+	atomic.StorePointer(p, unsafe.Pointer(&b))
+	if n := atomic.AddInt64(&s.size, 1); n > int64(maxLoad*len(t.buckets)) {
+		s.grow(t)
+	}
+}
+
+// grow publishes a copy of t with twice the buckets. s must be locked.
+func (s *shard[K, V]) grow(t *shardTable[K, V]) {
+	n := 2 * len(t.buckets)
+	buckets := make([][]entry[K, V], n)
+	for i := range t.buckets {
+		for _, e := range loadBucket[K, V](&t.buckets[i]) {
+			b := e.hash & uint64(n-1)
+			buckets[b] = append(buckets[b], e)
+		}
+	}
+	g := &shardTable[K, V]{buckets: make([]unsafe.Pointer, n)}
+	for i := range buckets {
+		if buckets[i] != nil {
+			g.buckets[i] = unsafe.Pointer(&buckets[i])
+		}
+	}
+	atomic.StorePointer(&s.table, unsafe.Pointer(g))
+}
+
+// Remove deletes k and reports whether it was there.
+func (m *ShardedTable[K, V]) Remove(k K) bool {
+	h := m.hashKey(k)
+	s := m.shardOf(h)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := lookup(s.load(), h, k); !ok {
+		return false
+	}
+	t := s.writable()
+	p := t.bucket(h)
+	old := loadBucket[K, V](p)
+	if len(old) == 1 {
+		atomic.StorePointer(p, nil)
+	} else {
+		b := make([]entry[K, V], 0, len(old)-1)
+		for _, e := range old {
+			if e.hash != h || e.key != k {
+				b = append(b, e)
+			}
+		}
+		atomic.StorePointer(p, unsafe.Pointer(&b))
+	}
+	atomic.AddInt64(&s.size, -1)
+	return true
+}
+
+// Size returns the number of entries. Writes running meanwhile may or may
+// not be counted.
+func (m *ShardedTable[K, V]) Size() int {
+	n := int64(0)
+	for i := range m.shards {
+		n += atomic.LoadInt64(&m.shards[i].size)
+	}
+	return int(n)
+}
+
+// Range calls f for every entry until it returns false, without locking.
+// Entries written meanwhile may or may not be seen, iterate a Snapshot
+// for a consistent view. f may change the table.
+func (m *ShardedTable[K, V]) Range(f func(k K, v V) bool) {
+	for i := range m.shards {
+		if !rangeTable(m.shards[i].load(), f) {
+			return
+		}
+	}
+}
+
+func rangeTable[K comparable, V any](t *shardTable[K, V], f func(k K, v V) bool) bool {
+	for i := range t.buckets {
+		for _, e := range loadBucket[K, V](&t.buckets[i]) {
+			if !f(e.key, e.value) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Snapshot is a read only copy of a ShardedTable at one point in time.
+type Snapshot[K comparable, V any] struct {
+	m      *ShardedTable[K, V]
+	tables []*shardTable[K, V]
+	size   int
+}
+
+// Snapshot returns the current content of the table. It costs a pass over
+// the shards, the entries are shared until written again.
+func (m *ShardedTable[K, V]) Snapshot() *Snapshot[K, V] {
+	for i := range m.shards {
+		m.shards[i].mu.Lock()
+	}
+	snap := &Snapshot[K, V]{m: m, tables: make([]*shardTable[K, V], len(m.shards))}
+	for i := range m.shards {
+		s := &m.shards[i]
+		t := s.load()
+		t.frozen = true
+		snap.tables[i] = t
+		snap.size += int(atomic.LoadInt64(&s.size))
+	}
+	for i := range m.shards {
+		m.shards[i].mu.Unlock()
+	}
+	return snap
+}
+
+func (s *Snapshot[K, V]) Get(k K) V {
+	v, _ := s.GetOK(k)
+	return v
+}
+
+func (s *Snapshot[K, V]) GetOK(k K) (V, bool) {
+	h := s.m.hashKey(k)
+	return lookup(s.tables[h>>s.m.shift], h, k)
+}
+
+func (s *Snapshot[K, V]) Size() int {
+	return s.size
+}
+
+// Range calls f for every entry of the snapshot until it returns false.
+func (s *Snapshot[K, V]) Range(f func(k K, v V) bool) {
+	for _, t := range s.tables {
+		if !rangeTable(t, f) {
+			return
+		}
+	}
+}
+
+// Items copies the snapshot into a map.
+func (s *Snapshot[K, V]) Items() map[K]V {
+	items := make(map[K]V, s.size)
+	s.Range(func(k K, v V) bool {
+		items[k] = v
+		return true
+	})
+	return items
+}
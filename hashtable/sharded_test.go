@@ -0,0 +1,213 @@
+package hashtable
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestShardedTable(t *testing.T) {
+	for _, shards := range []int{1, 3, 0} {
+		m := NewSharded[int, string](shards, nil)
+		want := map[int]string{}
+		for i := 0; i < 5000; i++ {
+			m.Put(i, fmt.Sprint(i))
+			want[i] = fmt.Sprint(i)
+		}
+		for i := 0; i < 5000; i += 3 {
+			if !m.Remove(i) {
+				t.Fatalf("%d not removed", i)
+			}
+			delete(want, i)
+		}
+		m.Put(1, "one")
+		want[1] = "one"
+		if m.Remove(0) || m.Size() != len(want) {
+			t.Fatalf("%d shards: size %d, want %d", shards, m.Size(), len(want))
+		}
+		got := map[int]string{}
+		m.Range(func(k int, v string) bool {
+			got[k] = v
+			return true
+		})
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("%d shards: entries differ", shards)
+		}
+		for k, v := range want {
+			if m.Get(k) != v {
+				t.Fatalf("%d shards: %d holds %q", shards, k, m.Get(k))
+			}
+		}
+	}
+}
+
+func TestShardedTable_Collisions(t *testing.T) {
+	m := NewSharded[string, int](4, func([]byte) uint64 { return 1 << 63 })
+	for i := 0; i < 100; i++ {
+		m.Put(fmt.Sprint(i), i)
+	}
+	for i := 0; i < 100; i += 2 {
+		m.Remove(fmt.Sprint(i))
+	}
+	for i := 0; i < 100; i++ {
+		if v, ok := m.GetOK(fmt.Sprint(i)); ok != (i%2 == 1) || ok && v != i {
+			t.Fatalf("%d: got %d %v", i, v, ok)
+		}
+	}
+}
+
+func TestShardedTable_Snapshot(t *testing.T) {
+	m := NewSharded[int, int](8, nil)
+	for i := 0; i < 1000; i++ {
+		m.Put(i, i)
+	}
+	snap := m.Snapshot()
+	want := snap.Items()
+	for i := 0; i < 1000; i++ {
+		m.Put(i, -i)
+		m.Put(1000+i, i)
+		if i%2 == 0 {
+			m.Remove(i)
+		}
+	}
+	if !reflect.DeepEqual(snap.Items(), want) || snap.Size() != 1000 || len(want) != 1000 {
+		t.Fatal("snapshot changed")
+	}
+	if v, ok := snap.GetOK(2); !ok || v != 2 || snap.Get(1500) != 0 {
+		t.Fatal("snapshot lookups see later writes")
+	}
+	again := m.Snapshot()
+	if again.Size() != 1500 || again.Get(1) != -1 || again.Get(1999) != 999 {
+		t.Fatal("new snapshot misses writes")
+	}
+}
+
+// TestShardedTable_Concurrent checks that snapshots taken while a writer
+// inserts 0, 1, 2, … always hold a prefix of the keys.
+func TestShardedTable_Concurrent(t *testing.T) {
+	const n = 5000
+	m := NewSharded[int, int](0, nil)
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(done)
+		for i := 0; i < n; i++ {
+			m.Put(i, i)
+		}
+	}()
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				snap := m.Snapshot()
+				size := snap.Size()
+				count := 0
+				snap.Range(func(k, v int) bool {
+					if k >= size || k != v {
+						t.Errorf("snapshot of %d holds %d: %d", size, k, v)
+						return false
+					}
+					count++
+					return true
+				})
+				if count != size {
+					t.Errorf("snapshot of %d has %d entries", size, count)
+					return
+				}
+				if k := size / 2; size > 0 && m.Get(k) != k {
+					t.Errorf("live read of %d", k)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if m.Size() != n {
+		t.Fatalf("size %d", m.Size())
+	}
+}
+
+func benchmarkKeys(n int) []string {
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("qbit1%040d", i)
+	}
+	return keys
+}
+
+// The parallel benchmarks compare the tables under concurrent readers,
+// run them with -cpu 1,2,4,8 to see how they scale.
+
+func BenchmarkParallelGet(b *testing.B) {
+	keys := benchmarkKeys(1 << 16)
+	ht := New[string, int](nil)
+	m := NewSharded[string, int](0, nil)
+	for i, k := range keys {
+		ht.Put(k, i)
+		m.Put(k, i)
+	}
+	run := func(get func(string) int) func(*testing.B) {
+		return func(b *testing.B) {
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					get(keys[i&(len(keys)-1)])
+					i += 7919
+				}
+			})
+		}
+	}
+	b.Run("HashTable", run(ht.Get))
+	b.Run("ShardedTable", run(m.Get))
+}
+
+// BenchmarkParallelMixed writes one time in ten.
+func BenchmarkParallelMixed(b *testing.B) {
+	keys := benchmarkKeys(1 << 16)
+	ht := New[string, int](nil)
+	m := NewSharded[string, int](0, nil)
+	for i, k := range keys {
+		ht.Put(k, i)
+		m.Put(k, i)
+	}
+	run := func(get func(string) int, put func(string, int)) func(*testing.B) {
+		return func(b *testing.B) {
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					k := keys[i&(len(keys)-1)]
+					if i%10 == 0 {
+						put(k, i)
+					} else {
+						get(k)
+					}
+					i += 7919
+				}
+			})
+		}
+	}
+	b.Run("HashTable", run(ht.Get, ht.Put))
+	b.Run("ShardedTable", run(m.Get, m.Put))
+}
+
+func BenchmarkSnapshot(b *testing.B) {
+	m := NewSharded[string, int](0, nil)
+	for i, k := range benchmarkKeys(1 << 16) {
+		m.Put(k, i)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.Snapshot()
+		m.Put("qbit1", i)
+	}
+}
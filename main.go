@@ -45,7 +45,10 @@ func main() {
 
 	//cmd.Execute()
 	raw, m := sdk.GetAddressSDK().GenerateMasterWalletAddress()
-	d := sdk.GetAddressSDK().DeriveFromMaster(raw, m)
+	d, err := sdk.GetAddressSDK().DeriveFromMaster(raw, "m/44'/906'/0'/0/0")
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	log.Printf("Master Key (to unlock your wallet): %s", m)
 	log.Printf("Your wallet address (long form): %s \n", d[:40])
@@ -0,0 +1,157 @@
+package protocol
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/quantosnetwork/Quantos/decoder"
+)
+
+/*
+
+	Debug rendering
+
+	Dump prints a payload as indented JSON:
+
+	integer          number
+	byte string      "text" when it is printable UTF-8, else "0x<hex>"
+	list             array
+	dictionary       object
+	hashtable        object with decimal keys and "@type": "hashtable"
+
+	Text starting with 0x is printed as hex, so "0x..." is always bytes.
+	Keys starting with @ get a second @ in front, leaving @type to the
+	annotations. Schema.Dump prints messages of a schema with their types
+	instead of guessing them.
+
+*/
+
+// Dump renders an encoded payload as annotated JSON. It does not need the
+// payload to be canonical, use Validate for that.
+func Dump(data []byte) ([]byte, error) {
+	var d decoder.Decoder
+	v, err := d.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	return indent(plain(v))
+}
+
+// object is a JSON object keeping the order of its members.
+type object []member
+
+type member struct {
+	key   string
+	value interface{}
+}
+
+func (o object) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		k, _ := json.Marshal(m.key)
+		b.Write(k)
+		b.WriteByte(':')
+		v, err := json.Marshal(m.value)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(v)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+func indent(v interface{}) ([]byte, error) {
+	return json.MarshalIndent(v, "", "  ")
+}
+
+// plain renders a decoded value without a schema.
+func plain(v interface{}) interface{} {
+	switch v := v.(type) {
+	case int64:
+		return json.Number(strconv.FormatInt(v, 10))
+	case *big.Int:
+		return json.Number(v.String())
+	case []byte:
+		return text(v)
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			list[i] = plain(item)
+		}
+		return list
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		o := make(object, 0, len(keys))
+		for _, k := range keys {
+			o = append(o, member{dictKey(k), plain(v[k])})
+		}
+		return o
+	case map[int]interface{}:
+		o, _ := table(v, func(_ int, item interface{}) (interface{}, error) {
+			return plain(item), nil
+		})
+		return o
+	}
+	return v
+}
+
+// table renders a hashtable, keys in increasing order.
+func table(t map[int]interface{}, render func(int, interface{}) (interface{}, error)) (object, error) {
+	keys := make([]int, 0, len(t))
+	for k := range t {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	o := object{{"@type", "hashtable"}}
+	for _, k := range keys {
+		v, err := render(k, t[k])
+		if err != nil {
+			return nil, err
+		}
+		o = append(o, member{strconv.Itoa(k), v})
+	}
+	return o, nil
+}
+
+func text(b []byte) string {
+	if isText(b) && !bytes.HasPrefix(b, []byte("0x")) {
+		return string(b)
+	}
+	return "0x" + hex.EncodeToString(b)
+}
+
+func isText(b []byte) bool {
+	if !utf8.Valid(b) {
+		return false
+	}
+	for _, r := range string(b) {
+		if !unicode.IsPrint(r) && r != '\n' && r != '\t' {
+			return false
+		}
+	}
+	return true
+}
+
+func dictKey(k string) string {
+	k = text([]byte(k))
+	if strings.HasPrefix(k, "@") {
+		return "@" + k
+	}
+	return k
+}
@@ -0,0 +1,127 @@
+package protocol
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/quantosnetwork/Quantos/crypto"
+	"github.com/quantosnetwork/Quantos/sdk/config"
+)
+
+//go:generate go run github.com/quantosnetwork/Quantos/encoder/quantosgen -type Envelope,Hello
+
+// MessageType identifies the payload of an envelope.
+type MessageType uint16
+
+const (
+	// MsgHello opens a connection, see Registry.Handshake.
+	MsgHello MessageType = iota + 1
+	MsgTx
+	MsgBlock
+	MsgVote
+)
+
+func (t MessageType) String() string {
+	switch t {
+	case MsgHello:
+		return "hello"
+	case MsgTx:
+		return "tx"
+	case MsgBlock:
+		return "block"
+	case MsgVote:
+		return "vote"
+	}
+	return fmt.Sprintf("message(%d)", uint16(t))
+}
+
+var (
+	ErrForeignChain = errors.New("quantos protocol: message for another chain")
+	ErrVersion      = errors.New("quantos protocol: unsupported protocol version")
+	ErrUnknownType  = errors.New("quantos protocol: unknown message type")
+	ErrUnsigned     = errors.New("quantos protocol: message must be signed")
+	ErrSignature    = errors.New("quantos protocol: invalid message signature")
+)
+
+// envelopeDomain separates envelope signatures from other uses of the key.
+const envelopeDomain = "envelope"
+
+// Envelope frames every message between nodes. Version is the protocol
+// version of the sender, config.Version, and Chain the network it runs
+// on. The signature covers the encoding of the envelope without Signer
+// and Signature.
+type Envelope struct {
+	Type      MessageType      `quantos:"type"`
+	Version   [2]byte          `quantos:"version"`
+	Chain     config.NetworkID `quantos:"chain"`
+	Payload   []byte           `quantos:"payload"`
+	Signer    []byte           `quantos:"signer"`
+	Signature []byte           `quantos:"sig"`
+}
+
+// Hello announces the protocol versions a node speaks, oldest and newest.
+type Hello struct {
+	Min [2]byte `quantos:"min"`
+	Max [2]byte `quantos:"max"`
+}
+
+// SigningBytes returns the message the envelope signature is made over.
+func (env *Envelope) SigningBytes() ([]byte, error) {
+	unsigned := *env
+	unsigned.Signer, unsigned.Signature = nil, nil
+	return Marshal(&unsigned)
+}
+
+// Sign signs the envelope with keys.
+func (env *Envelope) Sign(keys *crypto.HardenedKeys) error {
+	msg, err := env.SigningBytes()
+	if err != nil {
+		return err
+	}
+	signer, err := keys.PubKey.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	sig, err := keys.SignDomain(envelopeDomain, msg)
+	if err != nil {
+		return err
+	}
+	env.Signer, env.Signature = signer, sig
+	return nil
+}
+
+// Signed reports whether the envelope carries a signature.
+func (env *Envelope) Signed() bool {
+	return env.Signer != nil || env.Signature != nil
+}
+
+// VerifySignature checks the signature of a signed envelope.
+func (env *Envelope) VerifySignature() error {
+	if !env.Signed() {
+		return ErrUnsigned
+	}
+	pub, err := crypto.PublicKeyFromBytes(env.Signer)
+	if err != nil {
+		return ErrSignature
+	}
+	msg, err := env.SigningBytes()
+	if err != nil {
+		return err
+	}
+	if !crypto.VerifyDomain(pub, envelopeDomain, msg, env.Signature) {
+		return ErrSignature
+	}
+	return nil
+}
+
+// versionCmp compares two protocol versions, major byte first.
+func versionCmp(a, b [2]byte) int {
+	x, y := int(a[0])<<8|int(a[1]), int(b[0])<<8|int(b[1])
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
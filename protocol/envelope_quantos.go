@@ -0,0 +1,130 @@
+// Code generated by quantosgen. DO NOT EDIT.
+
+package protocol
+
+import (
+	"github.com/quantosnetwork/Quantos/decoder"
+	"github.com/quantosnetwork/Quantos/encoder"
+)
+
+// MarshalQuantos encodes x like encoder.Marshal.
+func (x *Envelope) MarshalQuantos(e *encoder.Encoder) error {
+	if x == nil {
+		return encoder.NilError(x)
+	}
+	e.BeginDict()
+	e.EncodeString("chain")
+	e.EncodeBytes(x.Chain[:])
+	if x.Payload != nil {
+		e.EncodeString("payload")
+		e.EncodeBytes(x.Payload)
+	}
+	if x.Signature != nil {
+		e.EncodeString("sig")
+		e.EncodeBytes(x.Signature)
+	}
+	if x.Signer != nil {
+		e.EncodeString("signer")
+		e.EncodeBytes(x.Signer)
+	}
+	e.EncodeString("type")
+	e.EncodeInt(int64(x.Type))
+	e.EncodeString("version")
+	e.EncodeBytes(x.Version[:])
+	e.End()
+	return nil
+}
+
+// UnmarshalQuantos decodes x like decoder.Unmarshal.
+func (x *Envelope) UnmarshalQuantos(d *decoder.Decoder) error {
+	if err := d.OpenDict(); err != nil {
+		return err
+	}
+	for d.More() {
+		key, err := d.DecodeBytes()
+		if err != nil {
+			return err
+		}
+		switch string(key) {
+		case "chain":
+			if err := d.DecodeByteArray(x.Chain[:]); err != nil {
+				return err
+			}
+		case "payload":
+			b1, err := d.DecodeBytes()
+			if err != nil {
+				return err
+			}
+			x.Payload = append([]byte(nil), b1...)
+		case "sig":
+			b2, err := d.DecodeBytes()
+			if err != nil {
+				return err
+			}
+			x.Signature = append([]byte(nil), b2...)
+		case "signer":
+			b3, err := d.DecodeBytes()
+			if err != nil {
+				return err
+			}
+			x.Signer = append([]byte(nil), b3...)
+		case "type":
+			n4, err := d.DecodeUint(16)
+			if err != nil {
+				return err
+			}
+			x.Type = MessageType(n4)
+		case "version":
+			if err := d.DecodeByteArray(x.Version[:]); err != nil {
+				return err
+			}
+		default:
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		}
+	}
+	return d.Close()
+}
+
+// MarshalQuantos encodes x like encoder.Marshal.
+func (x *Hello) MarshalQuantos(e *encoder.Encoder) error {
+	if x == nil {
+		return encoder.NilError(x)
+	}
+	e.BeginDict()
+	e.EncodeString("max")
+	e.EncodeBytes(x.Max[:])
+	e.EncodeString("min")
+	e.EncodeBytes(x.Min[:])
+	e.End()
+	return nil
+}
+
+// UnmarshalQuantos decodes x like decoder.Unmarshal.
+func (x *Hello) UnmarshalQuantos(d *decoder.Decoder) error {
+	if err := d.OpenDict(); err != nil {
+		return err
+	}
+	for d.More() {
+		key, err := d.DecodeBytes()
+		if err != nil {
+			return err
+		}
+		switch string(key) {
+		case "max":
+			if err := d.DecodeByteArray(x.Max[:]); err != nil {
+				return err
+			}
+		case "min":
+			if err := d.DecodeByteArray(x.Min[:]); err != nil {
+				return err
+			}
+		default:
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		}
+	}
+	return d.Close()
+}
@@ -0,0 +1,195 @@
+package protocol_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/quantosnetwork/Quantos/crypto"
+	"github.com/quantosnetwork/Quantos/protocol"
+	"github.com/quantosnetwork/Quantos/sdk/config"
+	"github.com/quantosnetwork/Quantos/tx"
+)
+
+func txRegistry(t *testing.T, chain config.NetworkID, got chan<- *tx.Transaction) *protocol.Registry {
+	r := protocol.NewRegistry(chain)
+	err := r.Register(protocol.MessageSpec{
+		Type:   protocol.MsgTx,
+		Schema: "Tx",
+		New:    func() interface{} { return new(tx.Transaction) },
+		Handle: func(env *protocol.Envelope, msg interface{}) error {
+			got <- msg.(*tx.Transaction)
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestEnvelopeDispatch(t *testing.T) {
+	got := make(chan *tx.Transaction, 1)
+	r := txRegistry(t, config.TESTNET, got)
+	keys := crypto.GenerateHardenedKeys()
+	env, err := r.Seal(protocol.MsgTx, testTx(), keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if env.Version != config.Version || env.Chain != config.TESTNET {
+		t.Fatalf("got %+v", env)
+	}
+	b, err := protocol.Marshal(env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := protocol.Messages.Validate("Envelope", b); err != nil {
+		t.Fatal(err)
+	}
+	var received protocol.Envelope
+	if err := protocol.Unmarshal(b, &received); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Dispatch(&received); err != nil {
+		t.Fatal(err)
+	}
+	if tx := <-got; tx.From != "qbit1from" || tx.Nonce != 7 {
+		t.Fatalf("got %+v", tx)
+	}
+}
+
+func TestEnvelopeRejects(t *testing.T) {
+	r := txRegistry(t, config.TESTNET, make(chan *tx.Transaction, 10))
+	if err := r.Register(protocol.MessageSpec{Type: protocol.MsgVote, Signed: true, Since: [2]byte{0, 2}}); err != nil {
+		t.Fatal(err)
+	}
+	keys := crypto.GenerateHardenedKeys()
+	seal := func(typ protocol.MessageType, msg interface{}, keys *crypto.HardenedKeys) *protocol.Envelope {
+		env, err := r.Seal(typ, msg, keys)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return env
+	}
+
+	foreign := seal(protocol.MsgTx, testTx(), nil)
+	foreign.Chain = config.LIVENET
+	newer := seal(protocol.MsgTx, testTx(), nil)
+	newer.Version = [2]byte{0, 2}
+	tampered := seal(protocol.MsgTx, testTx(), keys)
+	tampered.Payload[len(tampered.Payload)-2]++
+	halfSigned := seal(protocol.MsgTx, testTx(), keys)
+	halfSigned.Signature = nil
+	notTx := seal(protocol.MsgTx, map[string]interface{}{"nonce": 1}, nil)
+
+	for _, c := range []struct {
+		name string
+		env  *protocol.Envelope
+		want error
+	}{
+		{"foreign chain", foreign, protocol.ErrForeignChain},
+		{"newer version", newer, protocol.ErrVersion},
+		{"unregistered", seal(protocol.MsgBlock, "block", nil), protocol.ErrUnknownType},
+		{"hello", seal(protocol.MsgHello, &protocol.Hello{}, nil), protocol.ErrUnknownType},
+		{"before since", seal(protocol.MsgVote, "vote", keys), protocol.ErrUnknownType},
+		{"tampered", tampered, protocol.ErrSignature},
+		{"half signed", halfSigned, protocol.ErrSignature},
+	} {
+		if err := r.Dispatch(c.env); err != c.want {
+			t.Errorf("%s: got %v", c.name, err)
+		}
+	}
+	if err := r.Dispatch(notTx); err == nil {
+		t.Error("payload not matching the schema dispatched")
+	}
+
+	r.Version = [2]byte{0, 2}
+	if err := r.Dispatch(seal(protocol.MsgVote, "vote", nil)); err != protocol.ErrUnsigned {
+		t.Errorf("unsigned vote: got %v", err)
+	}
+	if err := r.Dispatch(seal(protocol.MsgVote, "vote", keys)); err != nil {
+		t.Errorf("signed vote: got %v", err)
+	}
+
+	if err := r.Register(protocol.MessageSpec{Type: protocol.MsgTx}); err == nil {
+		t.Error("type registered twice")
+	}
+	if err := r.Register(protocol.MessageSpec{Type: protocol.MsgHello}); err == nil {
+		t.Error("hello registered")
+	}
+	if err := r.Register(protocol.MessageSpec{Type: protocol.MsgBlock, Schema: "Nope"}); err == nil {
+		t.Error("unknown schema registered")
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	r := protocol.NewRegistry(config.TESTNET)
+	r.MinVersion, r.Version = [2]byte{0, 2}, [2]byte{1, 0}
+	for _, c := range []struct {
+		min, max, want [2]byte
+		err            error
+	}{
+		{[2]byte{0, 1}, [2]byte{0, 3}, [2]byte{0, 3}, nil},
+		{[2]byte{0, 5}, [2]byte{2, 0}, [2]byte{1, 0}, nil},
+		{[2]byte{0, 2}, [2]byte{0, 2}, [2]byte{0, 2}, nil},
+		{[2]byte{0, 0}, [2]byte{0, 1}, [2]byte{}, protocol.ErrVersion},
+		{[2]byte{1, 1}, [2]byte{1, 2}, [2]byte{}, protocol.ErrVersion},
+		{[2]byte{0, 4}, [2]byte{0, 3}, [2]byte{}, protocol.ErrVersion},
+	} {
+		v, err := r.Negotiate(&protocol.Hello{Min: c.min, Max: c.max})
+		if v != c.want || err != c.err {
+			t.Errorf("%v-%v: got %v %v", c.min, c.max, v, err)
+		}
+	}
+}
+
+func handshake(a, b *protocol.Registry) (pa, pb *protocol.Peer, ea, eb error) {
+	ca, cb := net.Pipe()
+	done := make(chan struct{})
+	go func() {
+		pb, eb = b.Handshake(cb)
+		close(done)
+	}()
+	pa, ea = a.Handshake(ca)
+	<-done
+	return
+}
+
+func TestHandshake(t *testing.T) {
+	got := make(chan *tx.Transaction, 1)
+	a := txRegistry(t, config.TESTNET, got)
+	b := txRegistry(t, config.TESTNET, got)
+	b.Version = [2]byte{0, 9}
+
+	pa, pb, ea, eb := handshake(a, b)
+	if ea != nil || eb != nil {
+		t.Fatal(ea, eb)
+	}
+	if pa.Version != config.Version || pb.Version != config.Version {
+		t.Fatalf("agreed on %v and %v", pa.Version, pb.Version)
+	}
+	go func() {
+		if err := pb.Send(protocol.MsgTx, testTx(), nil); err != nil {
+			t.Error(err)
+		}
+	}()
+	if err := pa.Receive(); err != nil {
+		t.Fatal(err)
+	}
+	if tx := <-got; tx.To != "qbit1to" {
+		t.Fatalf("got %+v", tx)
+	}
+	if err := pa.Send(protocol.MsgBlock, "block", nil); err != protocol.ErrUnknownType {
+		t.Fatalf("unregistered type sent: %v", err)
+	}
+
+	_, _, ea, eb = handshake(a, txRegistry(t, config.LIVENET, got))
+	if ea != protocol.ErrForeignChain || eb != protocol.ErrForeignChain {
+		t.Fatalf("foreign chain: %v %v", ea, eb)
+	}
+	c := txRegistry(t, config.TESTNET, got)
+	c.MinVersion, c.Version = [2]byte{0, 2}, [2]byte{0, 3}
+	_, _, ea, eb = handshake(a, c)
+	if ea != protocol.ErrVersion || eb != protocol.ErrVersion {
+		t.Fatalf("no common version: %v %v", ea, eb)
+	}
+}
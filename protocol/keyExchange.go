@@ -4,14 +4,14 @@ import (
 	"bufio"
 	"errors"
 	"fmt"
-	"github.com/open-quantum-safe/liboqs-go/oqs"
 	"github.com/quantosnetwork/Quantos/crypto"
 	"io"
 	"log"
 	"net"
+	"strconv"
 )
 
-const KEMNAME = "kyber512"
+const KEMNAME = crypto.KEMNAME
 
 type keyExchange interface {
 	initKemKX(host, port string)
@@ -45,7 +45,7 @@ func (kex KeyExchange) initKemKX(host, port string) {
 
 func (kex KeyExchange) handleKemKX(conn net.Conn) {
 	defer conn.Close()
-	_, err := fmt.Println(conn, KEMNAME)
+	_, err := fmt.Fprintln(conn, KEMNAME)
 	if err != nil {
 		conn.Close()
 	}
@@ -62,7 +62,7 @@ func (kex KeyExchange) handleKemKX(conn net.Conn) {
 		log.Fatal(err)
 		return
 	}
-	log.Printf("\nConnection #%d - server shared secret:\n% X ... % X\n\n", sharedSecret[0:8],
+	log.Printf("\nConnection %s - server shared secret:\n% X ... % X\n\n", conn.RemoteAddr(), sharedSecret[0:8],
 		sharedSecret[len(sharedSecret)-8:])
 
 	conn.Write([]byte("AUTHENTICATED"))
@@ -82,11 +82,10 @@ func StartKexClient() {
 			"to host on port 55225"))
 	}
 	defer conn.Close()
-	client := oqs.KeyEncapsulation{}
+	client := crypto.NewKeyEncapsulation()
 	defer client.Clean()
 	kemName, err := bufio.NewReader(conn).ReadString('\n')
 	if err != nil {
-		panic(err)
 		log.Fatal(errors.New("client cannot receive the " +
 			"KEM name from the server"))
 	}
@@ -111,8 +110,8 @@ func StartKexClient() {
 		log.Fatal(err)
 	} else if n != client.Details().LengthCiphertext {
 		log.Fatal(errors.New("client expected to read " +
-			string(client.Details().LengthCiphertext) + " bytes, but instead " +
-			"read " + string(n)))
+			strconv.Itoa(client.Details().LengthCiphertext) + " bytes, but instead " +
+			"read " + strconv.Itoa(n)))
 	}
 
 	// decapsulate the secret and extract the shared secret
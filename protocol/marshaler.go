@@ -1,6 +1,8 @@
 package protocol
 
 import (
+	"io"
+
 	"github.com/quantosnetwork/Quantos/decoder"
 	"github.com/quantosnetwork/Quantos/encoder"
 )
@@ -19,3 +21,19 @@ func Unmashal(data []byte) (interface{}, error) {
 	var d decoder.Decoder
 	return d.Decode(data)
 }
+
+// Unmarshal decodes data into the struct, slice, map or value v points to.
+func Unmarshal(data []byte, v interface{}) error {
+	return decoder.Unmarshal(data, v)
+}
+
+// NewEncoder returns an encoder writing values to w, e.g. a connection.
+func NewEncoder(w io.Writer) *encoder.StreamEncoder {
+	return encoder.NewStreamEncoder(w)
+}
+
+// NewDecoder returns a decoder reading values from r, within the default
+// size and depth limits.
+func NewDecoder(r io.Reader) *decoder.StreamDecoder {
+	return decoder.NewStreamDecoder(r)
+}
@@ -0,0 +1,255 @@
+package protocol
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/quantosnetwork/Quantos/crypto"
+	"github.com/quantosnetwork/Quantos/decoder"
+	"github.com/quantosnetwork/Quantos/encoder"
+	"github.com/quantosnetwork/Quantos/sdk/config"
+)
+
+// Handler processes a message received in env. msg is the value New of its
+// MessageSpec returned, filled from the payload, or the decoded payload
+// when New is nil.
+type Handler func(env *Envelope, msg interface{}) error
+
+// MessageSpec describes a message type of the catalogue.
+type MessageSpec struct {
+	Type MessageType
+	// Since is the first protocol version with this message, envelopes of
+	// older versions carrying it are rejected.
+	Since [2]byte
+	// Schema names the message of the registry schema the payload must
+	// match, if not empty.
+	Schema string
+	// Signed messages are rejected without a valid signature. Signatures
+	// are checked on every message that has one.
+	Signed bool
+	New    func() interface{}
+	Handle Handler
+}
+
+// Registry is the catalogue of the messages a node understands, on one
+// chain, and dispatches received envelopes to their handlers.
+type Registry struct {
+	Chain config.NetworkID
+	// MinVersion and Version bound the protocol versions accepted,
+	// Version is also the one sent.
+	MinVersion [2]byte
+	Version    [2]byte
+	// Schema checks the payloads of specs with a Schema name, Messages by
+	// default.
+	Schema *Schema
+
+	mu    sync.RWMutex
+	specs map[MessageType]*MessageSpec
+}
+
+// NewRegistry returns an empty catalogue for chain speaking config.Version
+// only.
+func NewRegistry(chain config.NetworkID) *Registry {
+	return &Registry{
+		Chain:      chain,
+		MinVersion: config.Version,
+		Version:    config.Version,
+		Schema:     Messages,
+		specs:      map[MessageType]*MessageSpec{},
+	}
+}
+
+// Register adds a message type to the catalogue.
+func (r *Registry) Register(spec MessageSpec) error {
+	if spec.Type == MsgHello {
+		return fmt.Errorf("quantos protocol: %s is reserved", spec.Type)
+	}
+	if spec.Schema != "" && r.Schema.messages[spec.Schema] == nil {
+		return fmt.Errorf("quantos protocol: unknown schema message %s", spec.Schema)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.specs[spec.Type] != nil {
+		return fmt.Errorf("quantos protocol: %s registered twice", spec.Type)
+	}
+	r.specs[spec.Type] = &spec
+	return nil
+}
+
+// Spec returns the registered spec of a message type.
+func (r *Registry) Spec(t MessageType) (*MessageSpec, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	spec, ok := r.specs[t]
+	return spec, ok
+}
+
+// Seal wraps msg into an envelope of type t, signed with keys when they
+// are not nil.
+func (r *Registry) Seal(t MessageType, msg interface{}, keys *crypto.HardenedKeys) (*Envelope, error) {
+	return r.seal(r.Version, t, msg, keys)
+}
+
+func (r *Registry) seal(version [2]byte, t MessageType, msg interface{}, keys *crypto.HardenedKeys) (*Envelope, error) {
+	payload, err := Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	env := &Envelope{Type: t, Version: version, Chain: r.Chain, Payload: payload}
+	if keys != nil {
+		if err := env.Sign(keys); err != nil {
+			return nil, err
+		}
+	}
+	return env, nil
+}
+
+// Open checks an envelope against the catalogue and decodes its payload:
+// it must be for this chain, of an accepted version, of a registered type
+// and carry a valid signature if it has one or its type needs one.
+func (r *Registry) Open(env *Envelope) (*MessageSpec, interface{}, error) {
+	if env.Chain != r.Chain {
+		return nil, nil, ErrForeignChain
+	}
+	if versionCmp(env.Version, r.MinVersion) < 0 || versionCmp(env.Version, r.Version) > 0 {
+		return nil, nil, ErrVersion
+	}
+	spec, ok := r.Spec(env.Type)
+	if !ok || versionCmp(env.Version, spec.Since) < 0 {
+		return nil, nil, ErrUnknownType
+	}
+	if spec.Signed || env.Signed() {
+		if err := env.VerifySignature(); err != nil {
+			return nil, nil, err
+		}
+	}
+	if spec.Schema != "" {
+		if err := r.Schema.Validate(spec.Schema, env.Payload); err != nil {
+			return nil, nil, err
+		}
+	}
+	if spec.New == nil {
+		msg, err := Unmashal(env.Payload)
+		return spec, msg, err
+	}
+	msg := spec.New()
+	if err := decoder.UnmarshalStrict(env.Payload, msg); err != nil {
+		return nil, nil, err
+	}
+	return spec, msg, nil
+}
+
+// Dispatch opens env and hands its message to the handler of its type.
+func (r *Registry) Dispatch(env *Envelope) error {
+	spec, msg, err := r.Open(env)
+	if err != nil {
+		return err
+	}
+	if spec.Handle == nil {
+		return nil
+	}
+	return spec.Handle(env, msg)
+}
+
+// Negotiate returns the newest protocol version both the registry and the
+// sender of h speak.
+func (r *Registry) Negotiate(h *Hello) ([2]byte, error) {
+	version, oldest := r.Version, r.MinVersion
+	if versionCmp(h.Max, version) < 0 {
+		version = h.Max
+	}
+	if versionCmp(h.Min, oldest) > 0 {
+		oldest = h.Min
+	}
+	if versionCmp(h.Min, h.Max) > 0 || versionCmp(version, oldest) < 0 {
+		return [2]byte{}, ErrVersion
+	}
+	return version, nil
+}
+
+/*
+
+	Connections
+
+	Both ends of a connection start by sending a hello envelope with the
+	versions they speak, then talk the version Negotiate picks. Envelopes
+	follow each other on the stream, each one encoded value, read within
+	the size limit of the stream decoder.
+
+*/
+
+// Peer is a connection to another node of the chain.
+type Peer struct {
+	registry *Registry
+	// Version is the protocol version agreed with the peer.
+	Version [2]byte
+	enc     *encoder.StreamEncoder
+	dec     *decoder.StreamDecoder
+}
+
+// Handshake exchanges hello envelopes over rw and agrees on a version. It
+// fails with ErrForeignChain or ErrVersion when the peer does not belong
+// to the chain or speaks no common version, rw should then be closed.
+func (r *Registry) Handshake(rw io.ReadWriter) (*Peer, error) {
+	p := &Peer{
+		registry: r,
+		enc:      encoder.NewStreamEncoder(rw),
+		dec:      decoder.NewStreamDecoder(rw),
+	}
+	p.dec.Strict = true
+	hello, err := r.seal(r.Version, MsgHello, &Hello{Min: r.MinVersion, Max: r.Version}, nil)
+	if err != nil {
+		return nil, err
+	}
+	// unbuffered connections only take a write once the other end reads
+	sent := make(chan error, 1)
+	go func() { sent <- p.enc.Encode(hello) }()
+
+	var env Envelope
+	if err := p.dec.DecodeValue(&env); err != nil {
+		return nil, err
+	}
+	if err := <-sent; err != nil {
+		return nil, err
+	}
+	if env.Chain != r.Chain {
+		return nil, ErrForeignChain
+	}
+	if env.Type != MsgHello {
+		return nil, fmt.Errorf("quantos protocol: expected hello, got %s", env.Type)
+	}
+	var h Hello
+	if err := decoder.UnmarshalStrict(env.Payload, &h); err != nil {
+		return nil, err
+	}
+	if p.Version, err = r.Negotiate(&h); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Send seals msg as a message of type t and writes it to the peer.
+func (p *Peer) Send(t MessageType, msg interface{}, keys *crypto.HardenedKeys) error {
+	if _, ok := p.registry.Spec(t); !ok {
+		return ErrUnknownType
+	}
+	env, err := p.registry.seal(p.Version, t, msg, keys)
+	if err != nil {
+		return err
+	}
+	return p.enc.Encode(env)
+}
+
+// Receive reads the next envelope from the peer and dispatches it.
+// Envelopes of another version than the agreed one are rejected.
+func (p *Peer) Receive() error {
+	var env Envelope
+	if err := p.dec.DecodeValue(&env); err != nil {
+		return err
+	}
+	if env.Version != p.Version {
+		return ErrVersion
+	}
+	return p.registry.Dispatch(&env)
+}
@@ -0,0 +1,433 @@
+package protocol
+
+import (
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/quantosnetwork/Quantos/decoder"
+)
+
+/*
+
+	Schemas
+
+	A schema names the messages of the protocol and the fields of their
+	dictionaries:
+
+	// comment
+	message Tx {
+		from    string
+		amount  uint256 optional
+		sigs    list<Signature> optional
+	}
+
+	A field is its key, its type and optional when it may be left out.
+	Other keys are rejected. Types are
+
+	int8 … int64, int        integers of that range
+	uint8 … uint64, uint
+	uint256, uint512
+	bigint                   any integer
+	bool                     i0e or i1e
+	bytes, bytes[N]          byte string, of N bytes
+	string                   UTF-8 byte string
+	list<T>                  list
+	map<T>                   dictionary
+	table<T>                 hashtable
+	any                      any value
+	Name                     the message Name, declared anywhere
+
+	Messages holds the schemas of the typed messages, messages.schema.
+
+*/
+
+//go:embed messages.schema
+var messagesSchema string
+
+// Messages describes the block, transaction and vote messages.
+var Messages = MustParseSchema(messagesSchema)
+
+type Schema struct {
+	messages map[string]*message
+}
+
+type message struct {
+	name   string
+	fields []schemaField
+}
+
+type schemaField struct {
+	key      string
+	t        *schemaType
+	optional bool
+}
+
+type schemaKind int
+
+const (
+	sInt schemaKind = iota
+	sBool
+	sBytes
+	sString
+	sList
+	sMap
+	sTable
+	sAny
+	sMessage
+)
+
+type schemaType struct {
+	kind   schemaKind
+	min    *big.Int // sInt, nil when unbounded
+	max    *big.Int
+	size   int // sBytes, 0 for any length
+	elem   *schemaType
+	name   string // sMessage
+	source string
+}
+
+var intRanges = map[string]struct {
+	bits   uint
+	signed bool
+}{
+	"int8": {8, true}, "int16": {16, true}, "int32": {32, true}, "int64": {64, true}, "int": {64, true},
+	"uint8": {8, false}, "uint16": {16, false}, "uint32": {32, false}, "uint64": {64, false}, "uint": {64, false},
+	"uint256": {256, false}, "uint512": {512, false},
+}
+
+// ParseSchema reads the messages declared in src.
+func ParseSchema(src string) (*Schema, error) {
+	s := &Schema{messages: map[string]*message{}}
+	var (
+		current *message
+		refs    []*schemaType
+	)
+	for n, line := range strings.Split(src, "\n") {
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		words := strings.Fields(line)
+		if len(words) == 0 {
+			continue
+		}
+		fail := func(format string, args ...interface{}) error {
+			return fmt.Errorf("quantos schema: line %d: %s", n+1, fmt.Sprintf(format, args...))
+		}
+		if current == nil {
+			if len(words) != 3 || words[0] != "message" || words[2] != "{" {
+				return nil, fail("expected message <name> {")
+			}
+			if !isIdent(words[1]) || isBuiltin(words[1]) {
+				return nil, fail("invalid message name %q", words[1])
+			}
+			if s.messages[words[1]] != nil {
+				return nil, fail("message %s declared twice", words[1])
+			}
+			current = &message{name: words[1]}
+			s.messages[current.name] = current
+			continue
+		}
+		if len(words) == 1 && words[0] == "}" {
+			current = nil
+			continue
+		}
+		if len(words) < 2 || len(words) > 3 || len(words) == 3 && words[2] != "optional" {
+			return nil, fail("expected <key> <type> [optional]")
+		}
+		for _, f := range current.fields {
+			if f.key == words[0] {
+				return nil, fail("field %s declared twice", words[0])
+			}
+		}
+		t, err := parseType(words[1], &refs)
+		if err != nil {
+			return nil, fail("%v", err)
+		}
+		current.fields = append(current.fields, schemaField{key: words[0], t: t, optional: len(words) == 3})
+	}
+	if current != nil {
+		return nil, fmt.Errorf("quantos schema: message %s is not closed", current.name)
+	}
+	for _, t := range refs {
+		if s.messages[t.name] == nil {
+			return nil, fmt.Errorf("quantos schema: unknown type %s", t.name)
+		}
+	}
+	return s, nil
+}
+
+// MustParseSchema is ParseSchema for schemas known to be valid, it panics
+// on errors.
+func MustParseSchema(src string) *Schema {
+	s, err := ParseSchema(src)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func isIdent(s string) bool {
+	for i, r := range s {
+		if r != '_' && (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (i == 0 || r < '0' || r > '9') {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func isBuiltin(name string) bool {
+	if _, ok := intRanges[name]; ok {
+		return true
+	}
+	switch name {
+	case "bigint", "bool", "bytes", "string", "list", "map", "table", "any", "message":
+		return true
+	}
+	return false
+}
+
+func parseType(src string, refs *[]*schemaType) (*schemaType, error) {
+	t := &schemaType{source: src}
+	if r, ok := intRanges[src]; ok {
+		t.kind = sInt
+		one := big.NewInt(1)
+		if r.signed {
+			t.max = new(big.Int).Lsh(one, r.bits-1)
+			t.min = new(big.Int).Neg(t.max)
+		} else {
+			t.max = new(big.Int).Lsh(one, r.bits)
+			t.min = new(big.Int)
+		}
+		t.max.Sub(t.max, one)
+		return t, nil
+	}
+	switch src {
+	case "bigint":
+		t.kind = sInt
+		return t, nil
+	case "bool":
+		t.kind = sBool
+		return t, nil
+	case "bytes":
+		t.kind = sBytes
+		return t, nil
+	case "string":
+		t.kind = sString
+		return t, nil
+	case "any":
+		t.kind = sAny
+		return t, nil
+	}
+	if strings.HasPrefix(src, "bytes[") && strings.HasSuffix(src, "]") {
+		n, err := strconv.Atoi(src[len("bytes[") : len(src)-1])
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid length in %s", src)
+		}
+		t.kind, t.size = sBytes, n
+		return t, nil
+	}
+	for prefix, kind := range map[string]schemaKind{"list<": sList, "map<": sMap, "table<": sTable} {
+		if strings.HasPrefix(src, prefix) && strings.HasSuffix(src, ">") {
+			elem, err := parseType(src[len(prefix):len(src)-1], refs)
+			if err != nil {
+				return nil, err
+			}
+			t.kind, t.elem = kind, elem
+			return t, nil
+		}
+	}
+	if !isIdent(src) || isBuiltin(src) {
+		return nil, fmt.Errorf("invalid type %s", src)
+	}
+	t.kind, t.name = sMessage, src
+	*refs = append(*refs, t)
+	return t, nil
+}
+
+// Names lists the messages of the schema.
+func (s *Schema) Names() []string {
+	names := make([]string, 0, len(s.messages))
+	for name := range s.messages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Validate checks that data is a canonical encoding of the message name.
+func (s *Schema) Validate(name string, data []byte) error {
+	v, err := decodeCanonical(data)
+	if err != nil {
+		return err
+	}
+	_, err = s.render(name, v)
+	return err
+}
+
+// Dump renders data, a name message, as JSON with the field types of the
+// schema. Messages get an "@type" member naming them. Unlike Validate it
+// accepts non-canonical input.
+func (s *Schema) Dump(name string, data []byte) ([]byte, error) {
+	var d decoder.Decoder
+	v, err := d.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	out, err := s.render(name, v)
+	if err != nil {
+		return nil, err
+	}
+	return indent(out)
+}
+
+func decodeCanonical(data []byte) (interface{}, error) {
+	d := decoder.Decoder{Strict: true}
+	return d.Decode(data)
+}
+
+func (s *Schema) render(name string, v interface{}) (interface{}, error) {
+	if s.messages[name] == nil {
+		return nil, fmt.Errorf("quantos schema: unknown message %s", name)
+	}
+	return s.value(&schemaType{kind: sMessage, name: name, source: name}, v, name)
+}
+
+func mismatch(path string, t *schemaType, v interface{}) error {
+	return fmt.Errorf("quantos schema: %s: %s is not a %s", path, describe(v), t.source)
+}
+
+func describe(v interface{}) string {
+	switch v.(type) {
+	case int64, *big.Int:
+		return "integer"
+	case []byte:
+		return "byte string"
+	case []interface{}:
+		return "list"
+	case map[string]interface{}:
+		return "dictionary"
+	case map[int]interface{}:
+		return "hashtable"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// value checks v against t and renders it, path locates v in errors.
+func (s *Schema) value(t *schemaType, v interface{}, path string) (interface{}, error) {
+	switch t.kind {
+	case sInt:
+		var n *big.Int
+		switch v := v.(type) {
+		case int64:
+			n = big.NewInt(v)
+		case *big.Int:
+			n = v
+		default:
+			return nil, mismatch(path, t, v)
+		}
+		if t.min != nil && (n.Cmp(t.min) < 0 || n.Cmp(t.max) > 0) {
+			return nil, fmt.Errorf("quantos schema: %s: %s overflows %s", path, n, t.source)
+		}
+		return json.Number(n.String()), nil
+	case sBool:
+		if i, ok := v.(int64); ok && (i == 0 || i == 1) {
+			return i == 1, nil
+		}
+		return nil, mismatch(path, t, v)
+	case sBytes:
+		b, ok := v.([]byte)
+		if !ok {
+			return nil, mismatch(path, t, v)
+		}
+		if t.size > 0 && len(b) != t.size {
+			return nil, fmt.Errorf("quantos schema: %s: %d bytes, want %d", path, len(b), t.size)
+		}
+		return "0x" + hex.EncodeToString(b), nil
+	case sString:
+		b, ok := v.([]byte)
+		if !ok || !utf8.Valid(b) {
+			return nil, mismatch(path, t, v)
+		}
+		return string(b), nil
+	case sList:
+		list, ok := v.([]interface{})
+		if !ok {
+			return nil, mismatch(path, t, v)
+		}
+		out := make([]interface{}, len(list))
+		for i, item := range list {
+			var err error
+			if out[i], err = s.value(t.elem, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	case sMap:
+		dict, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, mismatch(path, t, v)
+		}
+		keys := make([]string, 0, len(dict))
+		for k := range dict {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		o := make(object, 0, len(keys))
+		for _, k := range keys {
+			item, err := s.value(t.elem, dict[k], fmt.Sprintf("%s[%q]", path, k))
+			if err != nil {
+				return nil, err
+			}
+			o = append(o, member{dictKey(k), item})
+		}
+		return o, nil
+	case sTable:
+		ht, ok := v.(map[int]interface{})
+		if !ok {
+			return nil, mismatch(path, t, v)
+		}
+		return table(ht, func(k int, item interface{}) (interface{}, error) {
+			return s.value(t.elem, item, fmt.Sprintf("%s[%d]", path, k))
+		})
+	case sAny:
+		return plain(v), nil
+	case sMessage:
+		dict, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, mismatch(path, t, v)
+		}
+		m := s.messages[t.name]
+		o := object{{"@type", m.name}}
+		known := make(map[string]bool, len(m.fields))
+		for _, f := range m.fields {
+			known[f.key] = true
+			item, ok := dict[f.key]
+			if !ok {
+				if !f.optional {
+					return nil, fmt.Errorf("quantos schema: %s: missing field %s", path, f.key)
+				}
+				continue
+			}
+			out, err := s.value(f.t, item, path+"."+f.key)
+			if err != nil {
+				return nil, err
+			}
+			o = append(o, member{dictKey(f.key), out})
+		}
+		for k := range dict {
+			if !known[k] {
+				return nil, fmt.Errorf("quantos schema: %s: unknown field %q", path, k)
+			}
+		}
+		return o, nil
+	}
+	return nil, errors.New("quantos schema: invalid type")
+}
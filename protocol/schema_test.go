@@ -0,0 +1,167 @@
+package protocol_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/quantosnetwork/Quantos/address"
+	"github.com/quantosnetwork/Quantos/protocol"
+	"github.com/quantosnetwork/Quantos/sdk/config"
+	"github.com/quantosnetwork/Quantos/tx"
+)
+
+func TestDump(t *testing.T) {
+	b, err := protocol.Marshal(map[string]interface{}{
+		"name":  "spam",
+		"hash":  []byte{0xff, 0x00},
+		"hex":   "0x12",
+		"@type": "key",
+		"list":  []interface{}{int64(-1), uint64(1 << 63)},
+		"table": map[int]string{2: "b", 1: "a"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := protocol.Dump(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  "@@type": "key",
+  "hash": "0xff00",
+  "hex": "0x30783132",
+  "list": [
+    -1,
+    9223372036854775808
+  ],
+  "name": "spam",
+  "table": {
+    "@type": "hashtable",
+    "1": "a",
+    "2": "b"
+  }
+}`
+	if string(out) != want {
+		t.Fatalf("got\n%s", out)
+	}
+	if _, err := protocol.Dump(b[:len(b)-1]); err == nil {
+		t.Fatal("truncated payload dumped")
+	}
+}
+
+func testTx() *tx.Transaction {
+	return &tx.Transaction{
+		Type:    tx.Transfer,
+		Network: config.TESTNET,
+		From:    "qbit1from",
+		To:      "qbit1to",
+		Amount:  uint256.NewInt(1000),
+		Nonce:   7,
+		Signatures: []address.SignerSignature{
+			{PubKey: []byte{1, 2}, Signature: []byte{3, 4}},
+		},
+	}
+}
+
+func TestSchemaTx(t *testing.T) {
+	b, err := protocol.Marshal(testTx())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := protocol.Messages.Validate("Tx", b); err != nil {
+		t.Fatal(err)
+	}
+	out, err := protocol.Messages.Dump("Tx", b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  "@type": "Tx",
+  "type": 0,
+  "network": "0x0a00",
+  "from": "qbit1from",
+  "to": "qbit1to",
+  "amount": 1000,
+  "nonce": 7,
+  "sigs": [
+    {
+      "@type": "Signature",
+      "pk": "0x0102",
+      "sig": "0x0304"
+    }
+  ]
+}`
+	if string(out) != want {
+		t.Fatalf("got\n%s", out)
+	}
+}
+
+func TestSchemaRejects(t *testing.T) {
+	encode := func(v interface{}) []byte {
+		b, err := protocol.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+	tooBig := testTx()
+	tooBig.Amount = new(uint256.Int).SetAllOne()
+	valid := encode(testTx())
+	for _, c := range []struct {
+		name, msg string
+		data      []byte
+		err       string
+	}{
+		{"unknown message", "Nope", valid, "unknown message Nope"},
+		{"missing field", "Tx", encode(map[string]interface{}{"type": 0}), "missing field network"},
+		{"unknown field", "Signature", encode(map[string]interface{}{"pk": "a", "sig": "b", "x": 1}), `unknown field "x"`},
+		{"wrong type", "Signature", encode(map[string]interface{}{"pk": 1, "sig": "b"}), "Signature.pk: integer is not a bytes"},
+		{"range", "Vote", encode(map[string]interface{}{"block": make([]byte, 32), "height": 1, "validator": -1, "sig": ""}), "-1 overflows uint32"},
+		{"uint8", "Tx", bytes.Replace(valid, []byte("4:typei0e"), []byte("4:typei256e"), 1), "256 overflows uint8"},
+		{"length", "Tx", bytes.Replace(valid, []byte("7:network2:\n\x00"), []byte("7:network1:\n"), 1), "Tx.network: 1 bytes, want 2"},
+		{"nested", "Tx", bytes.Replace(valid, []byte("2:pk2:\x01\x02"), []byte("2:pki2e"), 1), "Tx.sigs[0].pk"},
+		{"non canonical", "Tx", bytes.Replace(valid, []byte("i7e"), []byte("i07e"), 1), "canonical"},
+	} {
+		err := protocol.Messages.Validate(c.msg, c.data)
+		if err == nil || !strings.Contains(err.Error(), c.err) {
+			t.Errorf("%s: got %v", c.name, err)
+		}
+	}
+	if err := protocol.Messages.Validate("Tx", encode(tooBig)); err != nil {
+		t.Fatalf("max uint256 amount: %v", err)
+	}
+}
+
+func TestParseSchema(t *testing.T) {
+	s, err := protocol.ParseSchema(`
+		message A { // comment
+			b  B optional
+			t  table<list<bytes[4]>>
+		}
+		message B {
+			x  any
+		}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := s.Names(); len(names) != 2 || names[0] != "A" || names[1] != "B" {
+		t.Fatalf("got %v", names)
+	}
+	for _, src := range []string{
+		"message A {\n x C\n}",
+		"message A {\n x int7\n}",
+		"message A {\n x bytes[0]\n}",
+		"message A {\n x int\n x int\n}",
+		"message A {\n x int\n}\nmessage A {\n}",
+		"message int {\n}",
+		"message A {\n x int required\n}",
+		"message A {",
+		"field int",
+	} {
+		if _, err := protocol.ParseSchema(src); err == nil {
+			t.Errorf("%q parsed", src)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package sdk
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quantosnetwork/Quantos/address"
+	"github.com/quantosnetwork/Quantos/crypto"
+	"github.com/quantosnetwork/Quantos/tx"
+	"lukechampine.com/frand"
+)
+
+// TxIndex is the chain index the account manager lists transactions from,
+// e.g. the Index of the tx Engine.
+type TxIndex interface {
+	Transactions(addr string) []*tx.Transaction
+}
+
+type accountManager struct {
+	store *address.AccountStore
+	index TxIndex
+}
+
+// NewAccountManager returns an account manager persisting accounts in
+// store. index may be nil when no chain is available.
+func NewAccountManager(store *address.AccountStore, index TxIndex) AccountManager {
+	return &accountManager{store: store, index: index}
+}
+
+// CreateNewAccount creates an account from a fresh random seed and stores
+// the seed in a keystore encrypted under passphrase.
+func (m *accountManager) CreateNewAccount(passphrase string) (*address.Account, error) {
+	seed := frand.Bytes(crypto.MaxSeedBytes)
+	a, err := address.NewAccount(CurrentNetworkID, seed)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.store.Create(a, seed, passphrase); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (m *accountManager) GetAddressFromAccount(id uuid.UUID) (string, error) {
+	for _, a := range m.store.Accounts() {
+		if a.ID == id {
+			return a.Address, nil
+		}
+	}
+	return "", address.ErrAccountNotFound
+}
+
+// account returns the account of an address or name.
+func (m *accountManager) account(addr string) (*address.Account, error) {
+	addr, err := ResolveAddress(addr)
+	if err != nil {
+		return nil, err
+	}
+	return m.store.Get(addr)
+}
+
+func (m *accountManager) GetAccountFromAddress(addr string) (*address.Account, error) {
+	return m.account(addr)
+}
+
+func (m *accountManager) Accounts() []*address.Account {
+	return m.store.Accounts()
+}
+
+// Wallet returns the HD root of an unlocked account.
+func (m *accountManager) Wallet(addr string) (*crypto.ExtendedKey, error) {
+	a, err := m.account(addr)
+	if err != nil {
+		return nil, err
+	}
+	return a.MasterKey()
+}
+
+// Keys returns a KeyManager over the signing keys of an unlocked account.
+func (m *accountManager) Keys(addr string) (KeyManager, error) {
+	a, err := m.account(addr)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := a.Keys()
+	if err != nil {
+		return nil, err
+	}
+	return NewKeyManager(keys), nil
+}
+
+// Authenticate unlocks an account for timeout, or until Lock when timeout
+// is 0.
+func (m *accountManager) Authenticate(addr string, passphrase string, timeout time.Duration) error {
+	a, err := m.account(addr)
+	if err != nil {
+		return err
+	}
+	return a.Unlock(passphrase, timeout)
+}
+
+func (m *accountManager) Lock(addr string) error {
+	a, err := m.account(addr)
+	if err != nil {
+		return err
+	}
+	a.Relock()
+	return nil
+}
+
+// Transactions lists the transactions of an account from the chain index.
+func (m *accountManager) Transactions(addr string) ([]*tx.Transaction, error) {
+	a, err := m.account(addr)
+	if err != nil {
+		return nil, err
+	}
+	if m.index == nil {
+		return nil, nil
+	}
+	return m.index.Transactions(a.Address), nil
+}
@@ -0,0 +1,74 @@
+package sdk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/quantosnetwork/Quantos/address"
+	"github.com/quantosnetwork/Quantos/sdk/config"
+	"github.com/quantosnetwork/Quantos/tx"
+)
+
+func TestAccountManager(t *testing.T) {
+	GetAddressSDK().InitSDK("test")
+	dir := t.TempDir()
+	store, err := address.OpenAccountStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.KDFParams = address.KDFParams{Time: 1, Memory: 1024, Threads: 1, KeyLen: 32}
+	engine := tx.NewEngine(config.TESTNET)
+	m := NewAccountManager(store, engine.Index())
+
+	a, err := m.CreateNewAccount("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr, _ := m.GetAddressFromAccount(a.ID); addr != a.Address {
+		t.Fatal("address lookup by id failed")
+	}
+	if _, err := m.Keys(a.Address); err != address.ErrKeystoreLocked {
+		t.Fatalf("keys of a locked account: %v", err)
+	}
+
+	// a new manager over the same directory sees the persisted account
+	store, err = address.OpenAccountStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m = NewAccountManager(store, engine.Index())
+	if len(m.Accounts()) != 1 {
+		t.Fatal("account was not persisted")
+	}
+	if err := m.Authenticate(a.Address, "wrong", 0); err != address.ErrKeystorePassphrase {
+		t.Fatalf("unlocked with a wrong passphrase: %v", err)
+	}
+	if err := m.Authenticate(a.Address, "secret", 50*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Keys(a.Address); err != nil {
+		t.Fatal(err)
+	}
+	acc, _ := m.GetAccountFromAddress(a.Address)
+	keys, err := acc.Keys()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	transfer := &tx.Transaction{Type: tx.Transfer, Network: config.TESTNET, From: a.Address, To: address.ZeroAddressOf(config.TESTNET)}
+	if err := transfer.Sign(keys); err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.Apply(transfer); err != nil {
+		t.Fatal(err)
+	}
+	txs, err := m.Transactions(a.Address)
+	if err != nil || len(txs) != 1 || txs[0] != transfer {
+		t.Fatalf("transactions of the account not listed: %v", err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if _, err := m.Keys(a.Address); err != address.ErrKeystoreLocked {
+		t.Fatalf("account still unlocked after the timeout: %v", err)
+	}
+}
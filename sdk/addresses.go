@@ -1,7 +1,10 @@
 package sdk
 
 import (
+	"errors"
 	"github.com/holiman/uint256"
+	"github.com/quantosnetwork/Quantos/address"
+	"github.com/quantosnetwork/Quantos/crypto"
 	"github.com/quantosnetwork/Quantos/sdk/config"
 	"github.com/quantosnetwork/Quantos/uint512"
 	"math/big"
@@ -10,46 +13,60 @@ import (
 type AddressSDK interface {
 	InitSDK(netID string)
 	GenerateMasterWalletAddress() (*uint512.Address, string)
-	VerifyAddress(in string, out bool)
-	IsZeroAddress(in string, out bool)
-	GenerateTXAddress(in InputData, out OutputData)
-	GenerateBlockAddress(in InputData, out OutputData)
+	VerifyAddress(in string) (bool, error)
+	IsZeroAddress(in string) (bool, error)
+	GenerateTXAddress(tx []byte) (string, error)
+	GenerateBlockAddress(block []byte) (string, error)
 	GetZeroAddress() string
-	DeriveFromMaster(master *uint512.Address, derivationPath string) string
+	DeriveFromMaster(master *uint512.Address, derivationPath string) (string, error)
+	ExportExtendedPublicKey(master *uint512.Address, accountPath string) (string, error)
+	DeriveFromExtendedPublicKey(xpub string, change, index uint32) (string, error)
 }
 
-type InputData struct {
-	data interface{}
-}
-
-type OutputData struct {
-	data string
-}
+var ErrAddressOtherNetwork = errors.New("quantos sdk: address is for another network")
 
 type addrFunctions struct{}
 
-func (a addrFunctions) IsZeroAddress(in string, out bool) {
-	//TODO implement me
-	panic("implement me")
+// IsZeroAddress reports whether in is the zero address of the current
+// network. Invalid addresses and addresses of other networks are errors.
+func (a addrFunctions) IsZeroAddress(in string) (bool, error) {
+	addr, err := parseNetworkAddress(in)
+	if err != nil {
+		return false, err
+	}
+	return addr.IsZero(), nil
+}
+
+// GenerateTXAddress returns the address of a serialised transaction.
+func (a addrFunctions) GenerateTXAddress(tx []byte) (string, error) {
+	return contentAddress(config.TX_ADDRESS_PREFIX, tx)
 }
 
-func (a addrFunctions) GenerateTXAddress(in InputData, out OutputData) {
-	//TODO implement me
-	panic("implement me")
+// GenerateBlockAddress returns the address of a serialised block.
+func (a addrFunctions) GenerateBlockAddress(block []byte) (string, error) {
+	return contentAddress(config.BLOCK_ADDRESS_PREFIX, block)
 }
 
-func (a addrFunctions) GenerateBlockAddress(in InputData, out OutputData) {
-	//TODO implement me
-	panic("implement me")
+func contentAddress(addrType uint32, content []byte) (string, error) {
+	if len(content) == 0 {
+		return "", errors.New("quantos sdk: cannot derive an address from empty content")
+	}
+	addr, err := address.FromContent(CurrentNetworkID, addrType, content)
+	if err != nil {
+		return "", err
+	}
+	return addr.String(), nil
 }
 
-var CurrentNetworkID config.NetworkID
+// CurrentNetworkID is the network the SDK works on, set by InitSDK.
+var CurrentNetworkID = config.LIVENET
 
 func (a addrFunctions) GenerateMasterWalletAddress() (*uint512.Address, string) {
 
 	addr := &uint512.Address{}
 	am := addr.Raw.Create()
-	m := am.Master()
+	// a created address holds its private key
+	m, _ := am.Master()
 	masterBig := new(big.Int).SetBytes(m)
 	out1, _ := uint256.FromBig(masterBig)
 	out := out1.String()
@@ -57,8 +74,69 @@ func (a addrFunctions) GenerateMasterWalletAddress() (*uint512.Address, string)
 	return am, out
 }
 
-func (a addrFunctions) DeriveFromMaster(master *uint512.Address, derivationPath string) string {
-	return master.Derive()
+func (a addrFunctions) DeriveFromMaster(master *uint512.Address, derivationPath string) (string, error) {
+	ctx, err := address.AddressContextFromPath(derivationPath)
+	if err != nil {
+		return "", err
+	}
+	child, err := master.Derive(ctx.Path())
+	if err != nil {
+		return "", err
+	}
+	return walletAddress(child.PublicKey())
+}
+
+// ExportExtendedPublicKey returns the watch-only qpub of an account, e.g.
+// m/44'/906'/0'. Receive addresses of the account can then be derived
+// without the private keys with DeriveFromExtendedPublicKey.
+func (a addrFunctions) ExportExtendedPublicKey(master *uint512.Address, accountPath string) (string, error) {
+	path, err := crypto.ParseDerivationPath(accountPath)
+	if err != nil {
+		return "", err
+	}
+	ext, err := master.ExtendedKey()
+	if err != nil {
+		return "", err
+	}
+	account, err := ext.DerivePath(path)
+	if err != nil {
+		return "", err
+	}
+	account.Network = CurrentNetworkID
+	return account.Neuter().Serialize()
+}
+
+// DeriveFromExtendedPublicKey derives the address at change/index below an
+// account qpub. It matches DeriveFromMaster with the account path followed
+// by change/index.
+func (a addrFunctions) DeriveFromExtendedPublicKey(xpub string, change, index uint32) (string, error) {
+	ext, err := crypto.ParseExtendedKey(xpub)
+	if err != nil {
+		return "", err
+	}
+	if ext.IsPrivate() {
+		return "", errors.New("quantos sdk: expected an extended public key")
+	}
+	if config.NetworkID(ext.Network) != CurrentNetworkID {
+		return "", errors.New("quantos sdk: extended key is for another network")
+	}
+	child, err := ext.DerivePath([]uint32{change, index})
+	if err != nil {
+		return "", err
+	}
+	pk, err := child.PubKey.MarshalBinary()
+	if err != nil {
+		return "", err
+	}
+	return walletAddress(pk)
+}
+
+func walletAddress(pk []byte) (string, error) {
+	addr, err := address.FromPublicKey(CurrentNetworkID, pk)
+	if err != nil {
+		return "", err
+	}
+	return addr.String(), nil
 }
 
 func (a addrFunctions) InitSDK(netID string) {
@@ -77,16 +155,32 @@ func (a addrFunctions) InitSDK(netID string) {
 	return
 }
 
-func initZeroAddress() {
-
+// VerifyAddress checks the format, checksum and network of an address.
+func (a addrFunctions) VerifyAddress(in string) (bool, error) {
+	if _, err := parseNetworkAddress(in); err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
-func (a addrFunctions) VerifyAddress(in string, out bool) {
-
+func parseNetworkAddress(in string) (*address.EncodedAddress, error) {
+	in, err := ResolveAddress(in)
+	if err != nil {
+		return nil, err
+	}
+	addr, err := address.ParseAddress(in)
+	if err != nil {
+		return nil, err
+	}
+	if addr.Network != CurrentNetworkID {
+		return nil, ErrAddressOtherNetwork
+	}
+	return addr, nil
 }
 
+// GetZeroAddress returns the zero address of the current network.
 func (a addrFunctions) GetZeroAddress() string {
-	return config.ZEROADDRESS
+	return address.ZeroAddressOf(CurrentNetworkID)
 }
 
 func GetAddressSDK() AddressSDK {
@@ -0,0 +1,132 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/quantosnetwork/Quantos/address"
+	"github.com/quantosnetwork/Quantos/crypto"
+	"github.com/quantosnetwork/Quantos/sdk/config"
+	"github.com/quantosnetwork/Quantos/tx"
+)
+
+func TestDeriveFromExtendedPublicKey(t *testing.T) {
+	a := GetAddressSDK()
+	a.InitSDK("test")
+	master, _ := a.GenerateMasterWalletAddress()
+
+	xpub, err := a.ExportExtendedPublicKey(master, "m/44'/906'/0'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	watchOnly, err := a.DeriveFromExtendedPublicKey(xpub, 0, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signing, err := a.DeriveFromMaster(master, "m/44'/906'/0'/0/5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if watchOnly != signing {
+		t.Fatalf("watch-only address %s differs from %s", watchOnly, signing)
+	}
+	again, _ := a.DeriveFromMaster(master, "m/44'/906'/0'/0/5")
+	if again != signing {
+		t.Fatal("DeriveFromMaster is not reproducible")
+	}
+
+	a.InitSDK("live")
+	defer a.InitSDK("test")
+	if _, err := a.DeriveFromExtendedPublicKey(xpub, 0, 5); err == nil {
+		t.Fatal("derived from a testnet qpub on livenet")
+	}
+}
+
+func TestVerifyAddress(t *testing.T) {
+	a := GetAddressSDK()
+	a.InitSDK("test")
+	master, _ := a.GenerateMasterWalletAddress()
+	addr, err := a.DeriveFromMaster(master, "m/44'/906'/0'/0/0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := a.VerifyAddress(addr); !ok || err != nil {
+		t.Fatalf("valid address rejected: %v", err)
+	}
+	typo := []byte(addr)
+	if typo[10] == 'q' {
+		typo[10] = 'p'
+	} else {
+		typo[10] = 'q'
+	}
+	if ok, err := a.VerifyAddress(string(typo)); ok || err == nil {
+		t.Fatal("address with a typo accepted")
+	}
+
+	zero, err := a.IsZeroAddress(a.GetZeroAddress())
+	if err != nil || !zero {
+		t.Fatalf("zero address not detected: %v", err)
+	}
+	if zero, _ := a.IsZeroAddress(addr); zero {
+		t.Fatal("wallet address detected as zero address")
+	}
+
+	a.InitSDK("live")
+	defer a.InitSDK("test")
+	if ok, err := a.VerifyAddress(addr); ok || err != ErrAddressOtherNetwork {
+		t.Fatalf("testnet address accepted on livenet: %v", err)
+	}
+}
+
+func TestContentAddresses(t *testing.T) {
+	a := GetAddressSDK()
+	a.InitSDK("test")
+	content := []byte("serialised content")
+
+	tx1, err := a.GenerateTXAddress(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx2, _ := a.GenerateTXAddress(content)
+	block, _ := a.GenerateBlockAddress(content)
+	if tx1 != tx2 {
+		t.Fatal("tx address is not derived from the content")
+	}
+	if tx1 == block {
+		t.Fatal("tx and block addresses collide")
+	}
+	if ok, err := a.VerifyAddress(block); !ok {
+		t.Fatalf("block address rejected: %v", err)
+	}
+	if _, err := a.GenerateTXAddress(nil); err == nil {
+		t.Fatal("address derived from empty content")
+	}
+}
+
+func TestAddressOrName(t *testing.T) {
+	a := GetAddressSDK()
+	a.InitSDK("test")
+	engine := tx.NewEngine(config.TESTNET)
+	SetNameResolver(engine)
+	defer SetNameResolver(nil)
+
+	keys := crypto.GenerateHardenedKeys()
+	pk, _ := keys.PubKey.MarshalBinary()
+	owner, _ := address.FromPublicKey(config.TESTNET, pk)
+	register := &tx.Transaction{Type: tx.RegisterName, Network: config.TESTNET, From: owner.String(), Data: []byte("alice.qbit")}
+	if err := register.Sign(keys); err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.Apply(register); err != nil {
+		t.Fatal(err)
+	}
+
+	if ok, err := a.VerifyAddress("alice.qbit"); !ok {
+		t.Fatalf("name rejected: %v", err)
+	}
+	if ok, _ := a.VerifyAddress("bob.qbit"); ok {
+		t.Fatal("unregistered name accepted")
+	}
+	if name, _ := LookupName(owner.String()); name != "alice.qbit" {
+		t.Fatalf("reverse lookup gave %q", name)
+	}
+}
@@ -29,4 +29,5 @@ const (
 	CONTRACT_ADDRESS_PREFIX
 )
 
-const ZEROADDRESS = "0xQ532Sbdoigjcofdhaylrzqxehahocf4G2Rm6V5Iam57Logocjyw4"
+// ZEROADDRESS is the zero (burn) address of the live network.
+const ZEROADDRESS = "qbit1qyuq5qgqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqv3l948"
@@ -0,0 +1,72 @@
+package sdk
+
+import (
+	"errors"
+
+	"github.com/quantosnetwork/Quantos/crypto"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/suites"
+)
+
+type keyManager struct {
+	keys *crypto.HardenedKeys
+}
+
+// NewKeyManager returns a KeyManager over keys, e.g. the keys of an
+// unlocked account. With nil keys a new pair is generated.
+func NewKeyManager(keys *crypto.HardenedKeys) KeyManager {
+	if keys == nil {
+		keys = crypto.GenerateHardenedKeys()
+	}
+	return &keyManager{keys: keys}
+}
+
+// GenerateKeyPair replaces the managed keys with a new pair.
+func (k *keyManager) GenerateKeyPair() *crypto.HardenedKeys {
+	k.keys = crypto.GenerateHardenedKeys()
+	return k.keys
+}
+
+// SignItem signs item under domain, e.g. "tx" or "vote". A signature only
+// verifies for the domain it was made for.
+func (k *keyManager) SignItem(domain string, item []byte) ([]byte, error) {
+	return k.keys.SignDomain(domain, item)
+}
+
+func (k *keyManager) ValidateSignedItem(signer kyber.Point, domain string, item []byte, sig []byte) (bool, error) {
+	if signer == nil {
+		return false, errors.New("quantos sdk: missing signer public key")
+	}
+	if !crypto.VerifyDomain(signer, domain, item, sig) {
+		return false, errors.New("quantos sdk: invalid signature")
+	}
+	return true, nil
+}
+
+func (k *keyManager) GetPublicKey() kyber.Point {
+	return k.keys.PubKey
+}
+
+func (k *keyManager) GetPrivateKey() kyber.Scalar {
+	return k.keys.PrivKey
+}
+
+func (k *keyManager) GetCurrentSuite() suites.Suite {
+	return k.keys.Suite
+}
+
+// ExchangeSecrets returns the symmetric key shared with the owner of peer.
+// Both sides derive the same key from their own private key.
+func (k *keyManager) ExchangeSecrets(peer kyber.Point) ([]byte, error) {
+	return crypto.DeriveSharedKey(k.keys, peer)
+}
+
+// Encrypt seals plaintext to the recipient's public key.
+func (k *keyManager) Encrypt(recipient kyber.Point, plaintext, aad []byte) ([]byte, error) {
+	return crypto.Encrypt(recipient, plaintext, aad)
+}
+
+// Decrypt opens a message encrypted to the managed public key.
+func (k *keyManager) Decrypt(ciphertext, aad []byte) ([]byte, error) {
+	return k.keys.Decrypt(ciphertext, aad)
+}
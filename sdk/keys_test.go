@@ -0,0 +1,88 @@
+package sdk
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/quantosnetwork/Quantos/crypto"
+)
+
+func TestKeyManagerEncryption(t *testing.T) {
+	alice := NewKeyManager(nil)
+	bob := NewKeyManager(nil)
+
+	msg := []byte("meet at block 1000")
+	aad := []byte("channel-7")
+	ct, err := alice.Encrypt(bob.GetPublicKey(), msg, aad)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pt, err := bob.Decrypt(ct, aad)
+	if err != nil || !bytes.Equal(pt, msg) {
+		t.Fatalf("decryption failed: %v", err)
+	}
+	if _, err := alice.Decrypt(ct, aad); err != crypto.ErrDecrypt {
+		t.Fatalf("decrypted by the wrong key: %v", err)
+	}
+	if _, err := bob.Decrypt(ct, []byte("channel-8")); err != crypto.ErrDecrypt {
+		t.Fatalf("decrypted with other additional data: %v", err)
+	}
+	ct[len(ct)-1] ^= 1
+	if _, err := bob.Decrypt(ct, aad); err != crypto.ErrDecrypt {
+		t.Fatalf("tampered ciphertext accepted: %v", err)
+	}
+	again, _ := alice.Encrypt(bob.GetPublicKey(), msg, aad)
+	if bytes.Equal(again[:64], ct[:64]) {
+		t.Fatal("encryption reused the ephemeral key")
+	}
+}
+
+func TestKeyManagerSignatures(t *testing.T) {
+	k := NewKeyManager(nil)
+	item := []byte("item")
+	sig, err := k.SignItem("vote", item)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := k.ValidateSignedItem(k.GetPublicKey(), "vote", item, sig); !ok {
+		t.Fatalf("valid signature rejected: %v", err)
+	}
+	if ok, _ := k.ValidateSignedItem(k.GetPublicKey(), "tx", item, sig); ok {
+		t.Fatal("signature accepted in another domain")
+	}
+	if ok, _ := k.ValidateSignedItem(NewKeyManager(nil).GetPublicKey(), "vote", item, sig); ok {
+		t.Fatal("signature accepted for another key")
+	}
+}
+
+func TestKeyManagerExchangeSecrets(t *testing.T) {
+	alice := NewKeyManager(nil)
+	bob := NewKeyManager(nil)
+	k1, err := alice.ExchangeSecrets(bob.GetPublicKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	k2, err := bob.ExchangeSecrets(alice.GetPublicKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(k1, k2) || len(k1) != 32 {
+		t.Fatal("shared keys differ")
+	}
+	low := alice.GetCurrentSuite().Point().Null()
+	if _, err := alice.ExchangeSecrets(low); err != crypto.ErrWeakPublicKey {
+		t.Fatalf("low order key accepted: %v", err)
+	}
+	// bob's key plus the point of order 2, (0, -1)
+	two := alice.GetCurrentSuite().Point()
+	if err := two.UnmarshalBinary(append([]byte{0xec}, append(bytes.Repeat([]byte{0xff}, 30), 0x7f)...)); err != nil {
+		t.Fatal(err)
+	}
+	mixed := alice.GetCurrentSuite().Point().Add(bob.GetPublicKey(), two)
+	if _, err := alice.ExchangeSecrets(mixed); err != crypto.ErrWeakPublicKey {
+		t.Fatalf("mixed order key accepted: %v", err)
+	}
+	if _, err := alice.Encrypt(mixed, []byte("msg"), nil); err != crypto.ErrWeakPublicKey {
+		t.Fatalf("encrypted to a mixed order key: %v", err)
+	}
+}
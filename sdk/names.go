@@ -0,0 +1,43 @@
+package sdk
+
+import (
+	"errors"
+
+	"github.com/quantosnetwork/Quantos/address"
+)
+
+// NameResolver resolves names of the name service, e.g. the tx Engine.
+type NameResolver interface {
+	Resolve(name string) (string, error)
+	LookupAddress(addr string) (string, error)
+}
+
+var nameResolver NameResolver
+
+var ErrNoNameResolver = errors.New("quantos sdk: no name resolver configured")
+
+// SetNameResolver sets the resolver used by every SDK call accepting an
+// address, they then accept a name like alice.qbit as well.
+func SetNameResolver(r NameResolver) {
+	nameResolver = r
+}
+
+// ResolveAddress returns in unchanged when it is an address and the
+// address it resolves to when it is a name.
+func ResolveAddress(in string) (string, error) {
+	if !address.IsName(in) {
+		return in, nil
+	}
+	if nameResolver == nil {
+		return "", ErrNoNameResolver
+	}
+	return nameResolver.Resolve(in)
+}
+
+// LookupName returns the name pointing to an address.
+func LookupName(addr string) (string, error) {
+	if nameResolver == nil {
+		return "", ErrNoNameResolver
+	}
+	return nameResolver.LookupAddress(addr)
+}
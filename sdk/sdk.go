@@ -1,11 +1,14 @@
 package sdk
 
 import (
+	"github.com/google/uuid"
 	"github.com/quantosnetwork/Quantos/address"
 	"github.com/quantosnetwork/Quantos/crypto"
+	"github.com/quantosnetwork/Quantos/tx"
 	"go.dedis.ch/kyber/v3"
 	"go.dedis.ch/kyber/v3/suites"
 	"net"
+	"time"
 )
 
 const SDKVERSION = 1
@@ -22,22 +25,25 @@ type QuantosSDK interface {
 
 type KeyManager interface {
 	GenerateKeyPair() *crypto.HardenedKeys
-	SignItem(item []byte) []byte
-	ValidateSignedItem(item []byte, sig []byte) (bool, error)
+	SignItem(domain string, item []byte) ([]byte, error)
+	ValidateSignedItem(signer kyber.Point, domain string, item []byte, sig []byte) (bool, error)
 	GetPublicKey() kyber.Point
 	GetPrivateKey() kyber.Scalar
 	GetCurrentSuite() suites.Suite
-	ExchangeSecrets() bool
-	Encrypt()
-	Decrypt()
+	ExchangeSecrets(peer kyber.Point) ([]byte, error)
+	Encrypt(recipient kyber.Point, plaintext, aad []byte) ([]byte, error)
+	Decrypt(ciphertext, aad []byte) ([]byte, error)
 }
 type AccountManager interface {
-	CreateNewAccount() *address.Account
-	GetAddressFromAccount() address.Address
-	GetAccountFromAddress(addr address.Address) *address.Account
-	Wallet()
-	Keys() KeyManager
-	Authenticate(args ...[]byte) bool
+	CreateNewAccount(passphrase string) (*address.Account, error)
+	GetAddressFromAccount(id uuid.UUID) (string, error)
+	GetAccountFromAddress(addr string) (*address.Account, error)
+	Accounts() []*address.Account
+	Wallet(addr string) (*crypto.ExtendedKey, error)
+	Keys(addr string) (KeyManager, error)
+	Authenticate(addr string, passphrase string, timeout time.Duration) error
+	Lock(addr string) error
+	Transactions(addr string) ([]*tx.Transaction, error)
 }
 type Protocol interface {
 	P2P()
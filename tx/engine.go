@@ -0,0 +1,188 @@
+package tx
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/quantosnetwork/Quantos/address"
+	"github.com/quantosnetwork/Quantos/sdk/config"
+)
+
+var (
+	ErrWrongNetwork     = errors.New("quantos tx: transaction is for another network")
+	ErrBadNonce         = errors.New("quantos tx: unexpected nonce")
+	ErrUnauthorized     = errors.New("quantos tx: signature does not match the sender")
+	ErrNotMultisig      = errors.New("quantos tx: sender is not a multisig account")
+	ErrMultisigExists   = errors.New("quantos tx: multisig account already registered")
+	ErrPolicyAddress    = errors.New("quantos tx: policy does not derive the sender address")
+	ErrPolicyVersion    = errors.New("quantos tx: rotated policy must be the next version")
+	ErrUnknownTxType    = errors.New("quantos tx: unknown transaction type")
+	ErrMissingRecipient = errors.New("quantos tx: transfer without recipient")
+	ErrInvalidSender    = errors.New("quantos tx: invalid sender address")
+	ErrInvalidRecipient = errors.New("quantos tx: invalid recipient address")
+)
+
+// Engine validates transactions and keeps the state they change: nonces,
+// the multisig policies and the names registered on chain. Applied
+// transactions are recorded in its Index.
+type Engine struct {
+	network  config.NetworkID
+	mu       sync.RWMutex
+	height   uint64
+	nonces   map[string]uint64
+	policies map[string]*address.MultisigPolicy
+	names    map[string]*NameEntry
+	index    *Index
+}
+
+func NewEngine(network config.NetworkID) *Engine {
+	return &Engine{
+		network:  network,
+		nonces:   make(map[string]uint64),
+		policies: make(map[string]*address.MultisigPolicy),
+		names:    make(map[string]*NameEntry),
+		index:    NewIndex(),
+	}
+}
+
+// Policy returns the current multisig policy of an account.
+func (e *Engine) Policy(addr string) (*address.MultisigPolicy, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	p, ok := e.policies[addr]
+	return p, ok
+}
+
+// SetHeight sets the current block height, names expire against it.
+func (e *Engine) SetHeight(h uint64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.height = h
+}
+
+// Nonce returns the nonce the next transaction of addr must carry.
+func (e *Engine) Nonce(addr string) uint64 {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.nonces[addr]
+}
+
+// Validate checks a transaction against the current state without
+// applying it.
+func (e *Engine) Validate(t *Transaction) error {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	_, err := e.validate(t)
+	return err
+}
+
+// Apply validates the transaction and updates the state.
+func (e *Engine) Apply(t *Transaction) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	change, err := e.validate(t)
+	if err != nil {
+		return err
+	}
+	if change != nil {
+		change()
+	}
+	e.nonces[t.From]++
+	e.index.Add(t)
+	return nil
+}
+
+func (e *Engine) Index() *Index {
+	return e.index
+}
+
+// validate returns the state change of the transaction besides the nonce,
+// nil for plain transfers.
+func (e *Engine) validate(t *Transaction) (func(), error) {
+	if t.Network != e.network {
+		return nil, ErrWrongNetwork
+	}
+	from, err := address.ParseAddress(t.From)
+	// only the canonical (lower case) form, the state is keyed by it
+	if err != nil || from.Network != e.network || from.String() != t.From {
+		return nil, ErrInvalidSender
+	}
+	if t.Nonce != e.nonces[t.From] {
+		return nil, ErrBadNonce
+	}
+	msg := t.SigningHash()
+	current := e.policies[t.From]
+
+	switch t.Type {
+	case Transfer:
+		if t.To == "" {
+			return nil, ErrMissingRecipient
+		}
+		if !e.canonical(t.To) {
+			return nil, ErrInvalidRecipient
+		}
+		return nil, e.authorize(from, current, msg, t.Signatures)
+
+	case CreateMultisig:
+		if current != nil {
+			return nil, ErrMultisigExists
+		}
+		policy, err := address.ParseMultisigPolicy(t.Data)
+		if err != nil {
+			return nil, err
+		}
+		addr, err := policy.Address(e.network)
+		if err != nil {
+			return nil, err
+		}
+		if !addr.Equal(from) {
+			return nil, ErrPolicyAddress
+		}
+		// the signers consent to the account as they would to a spend
+		if err := policy.Verify(msg, t.Signatures); err != nil {
+			return nil, err
+		}
+		return func() { e.policies[t.From] = policy }, nil
+
+	case RotateSigners:
+		if current == nil {
+			return nil, ErrNotMultisig
+		}
+		next, err := address.ParseMultisigPolicy(t.Data)
+		if err != nil {
+			return nil, err
+		}
+		if next.Version != current.Version+1 {
+			return nil, ErrPolicyVersion
+		}
+		if err := current.Verify(msg, t.Signatures); err != nil {
+			return nil, err
+		}
+		return func() { e.policies[t.From] = next }, nil
+
+	case RegisterName, RenewName, TransferName, SetNameRecord:
+		if err := e.authorize(from, current, msg, t.Signatures); err != nil {
+			return nil, err
+		}
+		return e.validateName(t)
+	}
+	return nil, ErrUnknownTxType
+}
+
+// authorize checks the signatures of a spend from an account: the policy
+// threshold for multisig accounts, else a single signature by the key the
+// address was derived from.
+func (e *Engine) authorize(from *address.EncodedAddress, policy *address.MultisigPolicy, msg []byte, sigs []address.SignerSignature) error {
+	if policy != nil {
+		return policy.Verify(msg, sigs)
+	}
+	if len(sigs) != 1 {
+		return ErrUnauthorized
+	}
+	owner, err := address.FromPublicKey(e.network, sigs[0].PubKey)
+	if err != nil || !owner.Equal(from) {
+		return ErrUnauthorized
+	}
+	single := &address.MultisigPolicy{Threshold: 1, Signers: [][]byte{sigs[0].PubKey}}
+	return single.Verify(msg, sigs)
+}
@@ -0,0 +1,153 @@
+package tx
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/quantosnetwork/Quantos/address"
+	"github.com/quantosnetwork/Quantos/crypto"
+	"github.com/quantosnetwork/Quantos/sdk/config"
+	"go.dedis.ch/kyber/v3"
+)
+
+func signers(n int) ([]*crypto.HardenedKeys, []kyber.Point) {
+	keys := make([]*crypto.HardenedKeys, n)
+	pubs := make([]kyber.Point, n)
+	for i := range keys {
+		keys[i] = crypto.GenerateHardenedKeys()
+		pubs[i] = keys[i].PubKey
+	}
+	return keys, pubs
+}
+
+func signed(t *testing.T, tx *Transaction, keys ...*crypto.HardenedKeys) *Transaction {
+	t.Helper()
+	for _, k := range keys {
+		if err := tx.Sign(k); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return tx
+}
+
+func TestMultisigPolicy(t *testing.T) {
+	e := NewEngine(config.TESTNET)
+	keys, pubs := signers(3)
+	policy, err := address.NewMultisigPolicy(2, pubs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	account, err := address.NewMultisigAccount(config.TESTNET, policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	to := address.ZeroAddressOf(config.TESTNET)
+
+	create := &Transaction{Type: CreateMultisig, Network: config.TESTNET, From: account.Address, Data: policy.Bytes()}
+	if err := e.Apply(signed(t, create, keys[0])); err != address.ErrMultisigNotEnough {
+		t.Fatalf("registered with one signature: %v", err)
+	}
+	create.Signatures = nil
+	if err := e.Apply(signed(t, create, keys[0], keys[2])); err != nil {
+		t.Fatal(err)
+	}
+
+	spend := func() *Transaction {
+		return &Transaction{Type: Transfer, Network: config.TESTNET, From: account.Address, To: to,
+			Amount: uint256.NewInt(10), Nonce: e.Nonce(account.Address)}
+	}
+	if err := e.Apply(signed(t, spend(), keys[1])); err != address.ErrMultisigNotEnough {
+		t.Fatalf("1 of 3 spend accepted: %v", err)
+	}
+	if err := e.Apply(signed(t, spend(), keys[1], keys[1])); err != address.ErrMultisigDuplicate {
+		t.Fatalf("duplicate signer accepted: %v", err)
+	}
+	outsider := crypto.GenerateHardenedKeys()
+	if err := e.Apply(signed(t, spend(), keys[1], outsider)); err != address.ErrMultisigUnknown {
+		t.Fatalf("outside signer accepted: %v", err)
+	}
+	forged := signed(t, spend(), keys[0], keys[1])
+	forged.Amount = uint256.NewInt(1000)
+	if err := e.Apply(forged); err != address.ErrMultisigBadSignature {
+		t.Fatalf("tampered spend accepted: %v", err)
+	}
+	ok := signed(t, spend(), keys[0], keys[1])
+	if err := e.Apply(ok); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Apply(ok); err != ErrBadNonce {
+		t.Fatalf("replayed spend accepted: %v", err)
+	}
+}
+
+func TestMultisigRotation(t *testing.T) {
+	e := NewEngine(config.TESTNET)
+	keys, pubs := signers(2)
+	policy, _ := address.NewMultisigPolicy(2, pubs)
+	account, _ := address.NewMultisigAccount(config.TESTNET, policy)
+	create := &Transaction{Type: CreateMultisig, Network: config.TESTNET, From: account.Address, Data: policy.Bytes()}
+	if err := e.Apply(signed(t, create, keys...)); err != nil {
+		t.Fatal(err)
+	}
+
+	newKeys, newPubs := signers(3)
+	next, err := policy.Rotate(2, newPubs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rotate := &Transaction{Type: RotateSigners, Network: config.TESTNET, From: account.Address, Nonce: 1, Data: next.Bytes()}
+	if err := e.Apply(signed(t, rotate, newKeys[0], newKeys[1])); err != address.ErrMultisigUnknown {
+		t.Fatalf("rotation signed by the new set accepted: %v", err)
+	}
+	rotate.Signatures = nil
+	if err := e.Apply(signed(t, rotate, keys...)); err != nil {
+		t.Fatal(err)
+	}
+	if p, _ := e.Policy(account.Address); p.Version != 1 || len(p.Signers) != 3 {
+		t.Fatal("policy not rotated")
+	}
+
+	to := address.ZeroAddressOf(config.TESTNET)
+	old := &Transaction{Type: Transfer, Network: config.TESTNET, From: account.Address, To: to, Nonce: 2}
+	if err := e.Apply(signed(t, old, keys...)); err != address.ErrMultisigUnknown {
+		t.Fatalf("spend by rotated out keys accepted: %v", err)
+	}
+	spend := &Transaction{Type: Transfer, Network: config.TESTNET, From: account.Address, To: to, Nonce: 2}
+	if err := e.Apply(signed(t, spend, newKeys[1], newKeys[2])); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSingleKeyTransfer(t *testing.T) {
+	e := NewEngine(config.TESTNET)
+	keys := crypto.GenerateHardenedKeys()
+	pk, _ := keys.PubKey.MarshalBinary()
+	from, _ := address.FromPublicKey(config.TESTNET, pk)
+	tx := &Transaction{Type: Transfer, Network: config.TESTNET, From: from.String(), To: address.ZeroAddressOf(config.TESTNET)}
+	if err := e.Apply(signed(t, tx, crypto.GenerateHardenedKeys())); err != ErrUnauthorized {
+		t.Fatalf("spend signed by another key accepted: %v", err)
+	}
+	tx.Signatures = nil
+	if err := e.Apply(signed(t, tx, keys)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTransferRecipient(t *testing.T) {
+	e := NewEngine(config.TESTNET)
+	alice, bob := newWallet(), newWallet()
+	pk, _ := bob.keys.PubKey.MarshalBinary()
+	live, _ := address.FromPublicKey(config.LIVENET, pk)
+	for _, to := range []string{live.String(), strings.ToUpper(bob.addr)} {
+		if err := alice.send(t, e, Transfer, to, nil); err != ErrInvalidRecipient {
+			t.Fatalf("transfer to %s: %v", to, err)
+		}
+	}
+	if err := alice.send(t, e, Transfer, bob.addr, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(e.Index().Transactions(strings.ToUpper(bob.addr))) != 1 || len(e.Index().Transactions(alice.addr)) != 1 {
+		t.Fatal("transfer not indexed under its accounts")
+	}
+}
@@ -0,0 +1,46 @@
+package tx
+
+import (
+	"sync"
+
+	"github.com/quantosnetwork/Quantos/address"
+)
+
+// Index lists the applied transactions of each address, as sender or
+// recipient, in the order they were applied. Addresses are indexed in
+// their canonical form.
+type Index struct {
+	mu     sync.RWMutex
+	byAddr map[string][]*Transaction
+}
+
+func NewIndex() *Index {
+	return &Index{byAddr: make(map[string][]*Transaction)}
+}
+
+func (i *Index) Add(t *Transaction) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	// the engine only accepts canonical senders
+	i.byAddr[t.From] = append(i.byAddr[t.From], t)
+	if to := canonicalAddress(t.To); to != "" && to != t.From {
+		i.byAddr[to] = append(i.byAddr[to], t)
+	}
+}
+
+// canonicalAddress returns s in the form String gives, s itself when it
+// does not parse.
+func canonicalAddress(s string) string {
+	a, err := address.ParseAddress(s)
+	if err != nil {
+		return s
+	}
+	return a.String()
+}
+
+// Transactions returns the transactions of addr.
+func (i *Index) Transactions(addr string) []*Transaction {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return append([]*Transaction(nil), i.byAddr[canonicalAddress(addr)]...)
+}
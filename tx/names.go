@@ -0,0 +1,225 @@
+package tx
+
+import (
+	"errors"
+
+	"github.com/quantosnetwork/Quantos/address"
+)
+
+/*
+
+	Name service
+
+	Names (alice.qbit) are registered on chain by transactions from their
+	owner:
+
+	RegisterName   Data = name, the sender becomes the owner
+	RenewName      Data = name, extends the expiry by NameRegistrationBlocks
+	TransferName   Data = name, To = new owner, the records are reset
+	SetNameRecord  Data = NameRecordData(name, key, value)
+
+	The "addr" record is the address a name resolves to, it starts as the
+	owner. A name resolves back from an address when its "addr" record
+	points to that address. An expired name can be registered again by
+	anyone.
+
+*/
+
+const (
+	// NameRegistrationBlocks is about one year of 15 seconds blocks.
+	NameRegistrationBlocks = 2102400
+
+	// AddrRecord is the resolver record holding the target address.
+	AddrRecord = "addr"
+
+	maxNameRecordKey   = 64
+	maxNameRecordValue = 1024
+)
+
+var (
+	ErrNameTaken    = errors.New("quantos names: name is registered")
+	ErrNameNotFound = errors.New("quantos names: name not found")
+	ErrNameOwner    = errors.New("quantos names: sender does not own the name")
+	ErrNameRecord   = errors.New("quantos names: invalid record")
+	ErrNameTarget   = errors.New("quantos names: target is not a canonical address of the network")
+)
+
+// NameEntry is the on-chain state of a registered name.
+type NameEntry struct {
+	Name    string
+	Owner   string
+	Expires uint64
+	Records map[string]string
+}
+
+// NameRecordData encodes the Data of a SetNameRecord transaction. An empty
+// value deletes the record.
+func NameRecordData(name, key, value string) []byte {
+	b := []byte{byte(len(name))}
+	b = append(b, name...)
+	b = append(b, byte(len(key)))
+	b = append(b, key...)
+	return append(b, value...)
+}
+
+func parseNameRecordData(b []byte) (name, key, value string, err error) {
+	if len(b) < 1 || len(b) < 1+int(b[0])+1 {
+		return "", "", "", ErrNameRecord
+	}
+	name = string(b[1 : 1+b[0]])
+	b = b[1+b[0]:]
+	if len(b) < 1+int(b[0]) {
+		return "", "", "", ErrNameRecord
+	}
+	key = string(b[1 : 1+b[0]])
+	value = string(b[1+b[0]:])
+	if key == "" || len(key) > maxNameRecordKey || len(value) > maxNameRecordValue {
+		return "", "", "", ErrNameRecord
+	}
+	return name, key, value, nil
+}
+
+// live returns the entry of name unless it expired.
+func (e *Engine) live(name string) (*NameEntry, bool) {
+	n, ok := e.names[name]
+	if !ok || n.Expires <= e.height {
+		return nil, false
+	}
+	return n, true
+}
+
+// validateName checks a name service transaction and returns the state
+// change it makes.
+func (e *Engine) validateName(t *Transaction) (func(), error) {
+	if t.Type == SetNameRecord {
+		name, key, value, err := parseNameRecordData(t.Data)
+		if err != nil {
+			return nil, err
+		}
+		n, err := e.ownedName(name, t.From)
+		if err != nil {
+			return nil, err
+		}
+		if key == AddrRecord && value != "" && !e.canonical(value) {
+			return nil, ErrNameTarget
+		}
+		return func() {
+			if value == "" {
+				delete(n.Records, key)
+			} else {
+				n.Records[key] = value
+			}
+		}, nil
+	}
+
+	name, err := address.NormalizeName(string(t.Data))
+	if err != nil || name != string(t.Data) {
+		return nil, address.ErrInvalidName
+	}
+	switch t.Type {
+	case RegisterName:
+		if _, ok := e.live(name); ok {
+			return nil, ErrNameTaken
+		}
+		return func() {
+			e.names[name] = &NameEntry{
+				Name:    name,
+				Owner:   t.From,
+				Expires: e.height + NameRegistrationBlocks,
+				Records: map[string]string{AddrRecord: t.From},
+			}
+		}, nil
+
+	case RenewName:
+		n, err := e.ownedName(name, t.From)
+		if err != nil {
+			return nil, err
+		}
+		return func() { n.Expires += NameRegistrationBlocks }, nil
+
+	case TransferName:
+		n, err := e.ownedName(name, t.From)
+		if err != nil {
+			return nil, err
+		}
+		if !e.canonical(t.To) {
+			return nil, ErrNameTarget
+		}
+		return func() {
+			n.Owner = t.To
+			n.Records = map[string]string{AddrRecord: t.To}
+		}, nil
+	}
+	return nil, ErrUnknownTxType
+}
+
+// canonical reports whether s is an address of the network in its canonical
+// form, the only one a sender can use, see validate.
+func (e *Engine) canonical(s string) bool {
+	a, err := address.ParseAddress(s)
+	return err == nil && a.Network == e.network && a.String() == s
+}
+
+func (e *Engine) ownedName(name, owner string) (*NameEntry, error) {
+	n, ok := e.live(name)
+	if !ok {
+		return nil, ErrNameNotFound
+	}
+	if n.Owner != owner {
+		return nil, ErrNameOwner
+	}
+	return n, nil
+}
+
+// Resolve returns the address name points to.
+func (e *Engine) Resolve(name string) (string, error) {
+	name, err := address.NormalizeName(name)
+	if err != nil {
+		return "", err
+	}
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	n, ok := e.live(name)
+	if !ok || n.Records[AddrRecord] == "" {
+		return "", ErrNameNotFound
+	}
+	return n.Records[AddrRecord], nil
+}
+
+// LookupAddress returns a live name pointing to addr, the first in
+// alphabetical order when several do.
+func (e *Engine) LookupAddress(addr string) (string, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	found := ""
+	for name := range e.names {
+		n, ok := e.live(name)
+		if ok && n.Records[AddrRecord] == addr && (found == "" || name < found) {
+			found = name
+		}
+	}
+	if found == "" {
+		return "", ErrNameNotFound
+	}
+	return found, nil
+}
+
+// NameEntry returns a copy of the state of a live name.
+func (e *Engine) NameEntry(name string) (*NameEntry, error) {
+	name, err := address.NormalizeName(name)
+	if err != nil {
+		return nil, err
+	}
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	n, ok := e.live(name)
+	if !ok {
+		return nil, ErrNameNotFound
+	}
+	c := *n
+	c.Records = make(map[string]string, len(n.Records))
+	for k, v := range n.Records {
+		c.Records[k] = v
+	}
+	return &c, nil
+}
@@ -0,0 +1,122 @@
+package tx
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/quantosnetwork/Quantos/address"
+	"github.com/quantosnetwork/Quantos/crypto"
+	"github.com/quantosnetwork/Quantos/sdk/config"
+)
+
+type wallet struct {
+	keys *crypto.HardenedKeys
+	addr string
+}
+
+func newWallet() *wallet {
+	keys := crypto.GenerateHardenedKeys()
+	pk, _ := keys.PubKey.MarshalBinary()
+	a, _ := address.FromPublicKey(config.TESTNET, pk)
+	return &wallet{keys: keys, addr: a.String()}
+}
+
+func (w *wallet) send(t *testing.T, e *Engine, typ Type, to string, data []byte) error {
+	t.Helper()
+	tx := &Transaction{Type: typ, Network: config.TESTNET, From: w.addr, To: to, Nonce: e.Nonce(w.addr), Data: data}
+	return e.Apply(signed(t, tx, w.keys))
+}
+
+func TestNameService(t *testing.T) {
+	e := NewEngine(config.TESTNET)
+	alice, bob := newWallet(), newWallet()
+
+	if err := alice.send(t, e, RegisterName, "", []byte("Alice.qbit")); err != address.ErrInvalidName {
+		t.Fatalf("non canonical name registered: %v", err)
+	}
+	if err := alice.send(t, e, RegisterName, "", []byte("alice.qbit")); err != nil {
+		t.Fatal(err)
+	}
+	if err := bob.send(t, e, RegisterName, "", []byte("alice.qbit")); err != ErrNameTaken {
+		t.Fatalf("registered a taken name: %v", err)
+	}
+	if addr, err := e.Resolve("ALICE.qbit"); err != nil || addr != alice.addr {
+		t.Fatalf("resolve: %s %v", addr, err)
+	}
+	if name, err := e.LookupAddress(alice.addr); err != nil || name != "alice.qbit" {
+		t.Fatalf("reverse lookup: %s %v", name, err)
+	}
+
+	if err := bob.send(t, e, SetNameRecord, "", NameRecordData("alice.qbit", "url", "https://bob.example")); err != ErrNameOwner {
+		t.Fatalf("record set by another account: %v", err)
+	}
+	if err := alice.send(t, e, SetNameRecord, "", NameRecordData("alice.qbit", "url", "https://alice.example")); err != nil {
+		t.Fatal(err)
+	}
+	if err := alice.send(t, e, SetNameRecord, "", NameRecordData("alice.qbit", AddrRecord, "not an address")); err == nil {
+		t.Fatal("invalid addr record accepted")
+	}
+	if n, _ := e.NameEntry("alice.qbit"); n.Records["url"] != "https://alice.example" {
+		t.Fatal("record not set")
+	}
+
+	if err := alice.send(t, e, TransferName, bob.addr, []byte("alice.qbit")); err != nil {
+		t.Fatal(err)
+	}
+	if addr, _ := e.Resolve("alice.qbit"); addr != bob.addr {
+		t.Fatal("transferred name does not resolve to the new owner")
+	}
+	if _, err := e.LookupAddress(alice.addr); err != ErrNameNotFound {
+		t.Fatalf("previous owner still resolves back: %v", err)
+	}
+	if err := alice.send(t, e, RenewName, "", []byte("alice.qbit")); err != ErrNameOwner {
+		t.Fatalf("renewed by the previous owner: %v", err)
+	}
+}
+
+func TestNameExpiry(t *testing.T) {
+	e := NewEngine(config.TESTNET)
+	alice, bob := newWallet(), newWallet()
+	if err := alice.send(t, e, RegisterName, "", []byte("alice.qbit")); err != nil {
+		t.Fatal(err)
+	}
+	e.SetHeight(NameRegistrationBlocks - 1)
+	if err := alice.send(t, e, RenewName, "", []byte("alice.qbit")); err != nil {
+		t.Fatal(err)
+	}
+	e.SetHeight(NameRegistrationBlocks + 1)
+	if _, err := e.Resolve("alice.qbit"); err != nil {
+		t.Fatal("renewed name expired")
+	}
+	e.SetHeight(2 * NameRegistrationBlocks)
+	if _, err := e.Resolve("alice.qbit"); err != ErrNameNotFound {
+		t.Fatalf("expired name resolves: %v", err)
+	}
+	if err := bob.send(t, e, RegisterName, "", []byte("alice.qbit")); err != nil {
+		t.Fatalf("expired name can not be registered again: %v", err)
+	}
+}
+
+func TestNameTargets(t *testing.T) {
+	e := NewEngine(config.TESTNET)
+	alice, bob := newWallet(), newWallet()
+	if err := alice.send(t, e, RegisterName, "", []byte("alice.qbit")); err != nil {
+		t.Fatal(err)
+	}
+	pk, _ := bob.keys.PubKey.MarshalBinary()
+	live, _ := address.FromPublicKey(config.LIVENET, pk)
+	for _, to := range []string{strings.ToUpper(bob.addr), live.String()} {
+		if err := alice.send(t, e, TransferName, to, []byte("alice.qbit")); err != ErrNameTarget {
+			t.Fatalf("transferred to %s: %v", to, err)
+		}
+		if err := alice.send(t, e, SetNameRecord, "", NameRecordData("alice.qbit", AddrRecord, to)); err != ErrNameTarget {
+			t.Fatalf("addr record set to %s: %v", to, err)
+		}
+	}
+	if err := alice.send(t, e, TransferName, bob.addr, []byte("alice.qbit")); err != nil {
+		t.Fatal(err)
+	}
+	if err := bob.send(t, e, RenewName, "", []byte("alice.qbit")); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,127 @@
+// Code generated by quantosgen. DO NOT EDIT.
+
+package tx
+
+import (
+	"github.com/holiman/uint256"
+	"github.com/quantosnetwork/Quantos/address"
+	"github.com/quantosnetwork/Quantos/decoder"
+	"github.com/quantosnetwork/Quantos/encoder"
+)
+
+// MarshalQuantos encodes x like encoder.Marshal.
+func (x *Transaction) MarshalQuantos(e *encoder.Encoder) error {
+	if x == nil {
+		return encoder.NilError(x)
+	}
+	e.BeginDict()
+	if x.Amount != nil {
+		e.EncodeString("amount")
+		e.EncodeUint256(x.Amount)
+	}
+	if x.Data != nil {
+		e.EncodeString("data")
+		e.EncodeBytes(x.Data)
+	}
+	e.EncodeString("from")
+	e.EncodeString(x.From)
+	e.EncodeString("network")
+	e.EncodeBytes(x.Network[:])
+	e.EncodeString("nonce")
+	e.EncodeUint(x.Nonce)
+	if x.Signatures != nil {
+		e.EncodeString("sigs")
+		e.BeginList()
+		for i1 := range x.Signatures {
+			if err := e.Encode(&x.Signatures[i1]); err != nil {
+				return err
+			}
+		}
+		e.End()
+	}
+	e.EncodeString("to")
+	e.EncodeString(x.To)
+	e.EncodeString("type")
+	e.EncodeInt(int64(x.Type))
+	e.End()
+	return nil
+}
+
+// UnmarshalQuantos decodes x like decoder.Unmarshal.
+func (x *Transaction) UnmarshalQuantos(d *decoder.Decoder) error {
+	if err := d.OpenDict(); err != nil {
+		return err
+	}
+	for d.More() {
+		key, err := d.DecodeBytes()
+		if err != nil {
+			return err
+		}
+		switch string(key) {
+		case "amount":
+			if x.Amount == nil {
+				x.Amount = new(uint256.Int)
+			}
+			if err := d.DecodeUint256(x.Amount); err != nil {
+				return err
+			}
+		case "data":
+			b2, err := d.DecodeBytes()
+			if err != nil {
+				return err
+			}
+			x.Data = append([]byte(nil), b2...)
+		case "from":
+			b3, err := d.DecodeBytes()
+			if err != nil {
+				return err
+			}
+			x.From = string(b3)
+		case "network":
+			if err := d.DecodeByteArray(x.Network[:]); err != nil {
+				return err
+			}
+		case "nonce":
+			n4, err := d.DecodeUint(64)
+			if err != nil {
+				return err
+			}
+			x.Nonce = n4
+		case "sigs":
+			{
+				if err := d.OpenList(); err != nil {
+					return err
+				}
+				s5 := make([]address.SignerSignature, 0)
+				for d.More() {
+					var v6 address.SignerSignature
+					if err := d.DecodeValue(&v6); err != nil {
+						return err
+					}
+					s5 = append(s5, v6)
+				}
+				if err := d.Close(); err != nil {
+					return err
+				}
+				x.Signatures = s5
+			}
+		case "to":
+			b7, err := d.DecodeBytes()
+			if err != nil {
+				return err
+			}
+			x.To = string(b7)
+		case "type":
+			n8, err := d.DecodeUint(8)
+			if err != nil {
+				return err
+			}
+			x.Type = Type(n8)
+		default:
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		}
+	}
+	return d.Close()
+}
@@ -1 +1,106 @@
 package tx
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/holiman/uint256"
+	"github.com/quantosnetwork/Quantos/address"
+	"github.com/quantosnetwork/Quantos/crypto"
+	"github.com/quantosnetwork/Quantos/sdk/config"
+	"github.com/zeebo/blake3"
+)
+
+type Type uint8
+
+const (
+	// Transfer moves Amount from From to To.
+	Transfer Type = iota
+	// CreateMultisig registers the version 0 multisig policy in Data for
+	// the From address.
+	CreateMultisig
+	// RotateSigners replaces the multisig policy of From with the next
+	// version in Data. It must be signed under the current policy.
+	RotateSigners
+	// RegisterName, RenewName, TransferName and SetNameRecord operate the
+	// name service, see names.go.
+	RegisterName
+	RenewName
+	TransferName
+	SetNameRecord
+)
+
+//go:generate go run github.com/quantosnetwork/Quantos/encoder/quantosgen -type Transaction
+
+type Transaction struct {
+	Type       Type                      `quantos:"type"`
+	Network    config.NetworkID          `quantos:"network"`
+	From       string                    `quantos:"from"`
+	To         string                    `quantos:"to"`
+	Amount     *uint256.Int              `quantos:"amount"`
+	Nonce      uint64                    `quantos:"nonce"`
+	Data       []byte                    `quantos:"data"`
+	Signatures []address.SignerSignature `quantos:"sigs"`
+}
+
+func writeBytes(h *blake3.Hasher, b []byte) {
+	var l [4]byte
+	binary.BigEndian.PutUint32(l[:], uint32(len(b)))
+	h.Write(l[:])
+	h.Write(b)
+}
+
+// SigningHash is the message signed by the signers of the transaction. It
+// covers every field except the signatures.
+func (t *Transaction) SigningHash() []byte {
+	h := blake3.NewDeriveKey("quantos-tx-signing")
+	h.Write([]byte{byte(t.Type)})
+	h.Write(t.Network[:])
+	writeBytes(h, []byte(t.From))
+	writeBytes(h, []byte(t.To))
+	var amount [32]byte
+	if t.Amount != nil {
+		amount = t.Amount.Bytes32()
+	}
+	h.Write(amount[:])
+	var nonce [8]byte
+	binary.BigEndian.PutUint64(nonce[:], t.Nonce)
+	h.Write(nonce[:])
+	writeBytes(h, t.Data)
+	return h.Sum(nil)
+}
+
+// Hash identifies the transaction including its signatures.
+func (t *Transaction) Hash() []byte {
+	h := blake3.NewDeriveKey("quantos-tx")
+	h.Write(t.SigningHash())
+	for _, s := range t.Signatures {
+		writeBytes(h, s.PubKey)
+		writeBytes(h, s.Signature)
+	}
+	return h.Sum(nil)
+}
+
+// Address is the content-derived address of the transaction.
+func (t *Transaction) Address() (string, error) {
+	a, err := address.FromContent(t.Network, config.TX_ADDRESS_PREFIX, t.Hash())
+	if err != nil {
+		return "", err
+	}
+	return a.String(), nil
+}
+
+// Sign adds the signature of keys. Multisig transactions are signed once
+// by each signer.
+func (t *Transaction) Sign(keys *crypto.HardenedKeys) error {
+	pk, err := keys.PubKey.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	sig := keys.Sign(t.SigningHash())
+	if sig == nil {
+		return errors.New("quantos tx: signing failed")
+	}
+	t.Signatures = append(t.Signatures, address.SignerSignature{PubKey: pk, Signature: sig})
+	return nil
+}
@@ -0,0 +1,182 @@
+package uint512
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/quantosnetwork/Quantos/crypto"
+)
+
+/*
+
+	Address encoding (version 1)
+
+	[1]byte  version (1)
+	[64]byte raw value, big-endian
+	[32]byte public key
+	[8]byte  timestamp, unix nanoseconds, big-endian
+	[64]byte signature of the public key
+	[64]byte signature of the timestamp
+
+	The JSON encoding holds the same fields, hex encoded. Only the public
+	part is encoded, use PrivateKey to export the key itself. Loading an
+	address checks both signatures against the public key, that the raw
+	value is the one of the key and that the timestamp is not in the
+	future.
+
+*/
+
+const (
+	AddressEncodingVersion = 1
+
+	addressPKLen  = 32
+	addressSigLen = 64
+	addressEncLen = 1 + 64 + addressPKLen + 8 + 2*addressSigLen
+)
+
+// MaxTimestampSkew is how far in the future a signed timestamp may be.
+var MaxTimestampSkew = 2 * time.Minute
+
+var (
+	ErrAddressEncoding  = errors.New("quantos address: invalid address encoding")
+	ErrAddressSignature = errors.New("quantos address: invalid address signature")
+	ErrAddressTimestamp = errors.New("quantos address: invalid address timestamp")
+	ErrNoPrivateKey     = errors.New("quantos address: address has no private key")
+)
+
+func (addr *Address) MarshalBinary() ([]byte, error) {
+	if addr.Raw == nil || len(addr.pk) != addressPKLen ||
+		len(addr.Signature) != addressSigLen || len(addr.TimestampSigned) != addressSigLen {
+		return nil, ErrAddressEncoding
+	}
+	b := make([]byte, 0, addressEncLen)
+	b = append(b, AddressEncodingVersion)
+	b = append(b, addr.Raw.Merge().Bytes()...)
+	b = append(b, addr.pk...)
+	var ts [8]byte
+	binary.BigEndian.PutUint64(ts[:], uint64(addr.Timestamp))
+	b = append(b, ts[:]...)
+	b = append(b, addr.Signature...)
+	return append(b, addr.TimestampSigned...), nil
+}
+
+// UnmarshalBinary loads an encoded address and verifies it.
+func (addr *Address) UnmarshalBinary(b []byte) error {
+	if len(b) != addressEncLen {
+		return ErrAddressEncoding
+	}
+	if b[0] != AddressEncodingVersion {
+		return errors.New("quantos address: unsupported address encoding version")
+	}
+	b = b[1:]
+	a := &Address{Raw: &address{NewUint512FromBytes(b[:32], b[32:64])}}
+	b = b[64:]
+	a.pk = append([]byte(nil), b[:addressPKLen]...)
+	b = b[addressPKLen:]
+	a.Timestamp = int64(binary.BigEndian.Uint64(b))
+	b = b[8:]
+	a.Signature = append([]byte(nil), b[:addressSigLen]...)
+	a.TimestampSigned = append([]byte(nil), b[addressSigLen:]...)
+	if err := a.Verify(); err != nil {
+		return err
+	}
+	*addr = *a
+	return nil
+}
+
+type addressJSON struct {
+	Version            int    `json:"version"`
+	Raw                string `json:"raw"`
+	PublicKey          string `json:"publicKey"`
+	Timestamp          int64  `json:"timestamp"`
+	Signature          string `json:"signature"`
+	TimestampSignature string `json:"timestampSignature"`
+}
+
+func (addr *Address) MarshalJSON() ([]byte, error) {
+	if _, err := addr.MarshalBinary(); err != nil {
+		return nil, err
+	}
+	return json.Marshal(addressJSON{
+		Version:            AddressEncodingVersion,
+		Raw:                hex.EncodeToString(addr.Raw.Merge().Bytes()),
+		PublicKey:          hex.EncodeToString(addr.pk),
+		Timestamp:          addr.Timestamp,
+		Signature:          hex.EncodeToString(addr.Signature),
+		TimestampSignature: hex.EncodeToString(addr.TimestampSigned),
+	})
+}
+
+// UnmarshalJSON loads a JSON encoded address and verifies it.
+func (addr *Address) UnmarshalJSON(data []byte) error {
+	var j addressJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if j.Version != AddressEncodingVersion {
+		return errors.New("quantos address: unsupported address encoding version")
+	}
+	var b bytes.Buffer
+	b.WriteByte(byte(j.Version))
+	for _, field := range []string{j.Raw, j.PublicKey} {
+		raw, err := hex.DecodeString(field)
+		if err != nil {
+			return ErrAddressEncoding
+		}
+		b.Write(raw)
+	}
+	var ts [8]byte
+	binary.BigEndian.PutUint64(ts[:], uint64(j.Timestamp))
+	b.Write(ts[:])
+	for _, field := range []string{j.Signature, j.TimestampSignature} {
+		raw, err := hex.DecodeString(field)
+		if err != nil {
+			return ErrAddressEncoding
+		}
+		b.Write(raw)
+	}
+	return addr.UnmarshalBinary(b.Bytes())
+}
+
+// LoadAddress decodes and verifies a binary encoded address.
+func LoadAddress(b []byte) (*Address, error) {
+	a := new(Address)
+	if err := a.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Verify checks the signatures, raw value and timestamp of the address.
+func (addr *Address) Verify() error {
+	pub, err := crypto.PublicKeyFromBytes(addr.pk)
+	if err != nil {
+		return ErrAddressEncoding
+	}
+	if addr.Raw == nil || !addr.Raw.Merge().Eq(rawFromPublicKey(addr.pk).Merge()) {
+		return ErrAddressEncoding
+	}
+	if !crypto.VerifySchnorr(pub, addr.pk, addr.Signature) {
+		return ErrAddressSignature
+	}
+	if addr.Timestamp <= 0 || time.Unix(0, addr.Timestamp).After(time.Now().Add(MaxTimestampSkew)) {
+		return ErrAddressTimestamp
+	}
+	if !crypto.VerifySchnorr(pub, timestampMessage(addr.Timestamp), addr.TimestampSigned) {
+		return ErrAddressSignature
+	}
+	return nil
+}
+
+// PrivateKey returns a copy of the marshalled private key, to be kept in
+// a keystore. AddressFromPrivateKey imports it back.
+func (addr *Address) PrivateKey() ([]byte, error) {
+	if len(addr.sk) == 0 {
+		return nil, ErrNoPrivateKey
+	}
+	return append([]byte(nil), addr.sk...), nil
+}
@@ -0,0 +1,101 @@
+package uint512
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"lukechampine.com/frand"
+)
+
+func testMaster(t *testing.T) *Address {
+	t.Helper()
+	a, err := NewMasterAddress(frand.Bytes(64))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func TestAddressBinaryRoundTrip(t *testing.T) {
+	a := testMaster(t)
+	b, err := a.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadAddress(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(loaded.PublicKey(), a.PublicKey()) || loaded.Timestamp != a.Timestamp {
+		t.Fatal("loaded address differs")
+	}
+	again, _ := loaded.MarshalBinary()
+	if !bytes.Equal(again, b) {
+		t.Fatal("encoding is not deterministic")
+	}
+	if _, err := loaded.PrivateKey(); err != ErrNoPrivateKey {
+		t.Fatalf("a loaded address has no private key: %v", err)
+	}
+	if _, err := loaded.Master(); err != ErrNoPrivateKey {
+		t.Fatalf("master of a loaded address: %v", err)
+	}
+}
+
+func TestAddressJSONRoundTrip(t *testing.T) {
+	a := testMaster(t)
+	j, err := json.Marshal(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var loaded Address
+	if err := json.Unmarshal(j, &loaded); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(loaded.Serialize(), a.Serialize()) {
+		t.Fatal("JSON round trip differs")
+	}
+}
+
+func TestAddressLoadVerifies(t *testing.T) {
+	a := testMaster(t)
+	b, _ := a.MarshalBinary()
+
+	for _, off := range []int{1, 65, 100, 110, 180} {
+		bad := append([]byte(nil), b...)
+		bad[off] ^= 1
+		if _, err := LoadAddress(bad); err == nil {
+			t.Fatalf("tampered byte %d accepted", off)
+		}
+	}
+
+	// a timestamp in the future, even when correctly signed
+	k, _ := a.ExtendedKey()
+	keys, _ := k.HardenedKeys()
+	future := time.Now().Add(time.Hour).UnixNano()
+	a.Timestamp = future
+	a.TimestampSigned = keys.Sign(timestampMessage(future))
+	b, _ = a.MarshalBinary()
+	if _, err := LoadAddress(b); err != ErrAddressTimestamp {
+		t.Fatalf("future timestamp accepted: %v", err)
+	}
+}
+
+func TestPrivateKeyExport(t *testing.T) {
+	a := testMaster(t)
+	sk, err := a.PrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	imported, err := AddressFromPrivateKey(sk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(imported.PublicKey(), a.PublicKey()) {
+		t.Fatal("imported key has another public key")
+	}
+	if err := imported.Verify(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,418 @@
+package uint512
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"math/big"
+	"math/bits"
+
+	"github.com/holiman/uint256"
+)
+
+// Int is a fixed width 512 bit unsigned integer, little-endian limbs like
+// uint256.Int. Arithmetic wraps modulo 2^512, the *Overflow variants
+// report when it did. Division by zero gives zero.
+type Int [8]uint64
+
+// NewInt returns a new Int set to v.
+func NewInt(v uint64) *Int {
+	return &Int{v}
+}
+
+func (z *Int) SetUint64(v uint64) *Int {
+	*z = Int{v}
+	return z
+}
+
+func (z *Int) Set(x *Int) *Int {
+	*z = *x
+	return z
+}
+
+func (z *Int) Clone() *Int {
+	c := *z
+	return &c
+}
+
+func (z *Int) Clear() *Int {
+	*z = Int{}
+	return z
+}
+
+func (z *Int) IsZero() bool {
+	return *z == Int{}
+}
+
+// FromUint256 widens x.
+func FromUint256(x *uint256.Int) *Int {
+	return &Int{x[0], x[1], x[2], x[3]}
+}
+
+// Uint256 returns the low 256 bits and whether the high ones were set.
+func (z *Int) Uint256() (*uint256.Int, bool) {
+	return &uint256.Int{z[0], z[1], z[2], z[3]}, z[4]|z[5]|z[6]|z[7] != 0
+}
+
+// FromHiLo returns hi*2^256 + lo.
+func FromHiLo(hi, lo *uint256.Int) *Int {
+	return &Int{lo[0], lo[1], lo[2], lo[3], hi[0], hi[1], hi[2], hi[3]}
+}
+
+// HiLo splits z into its high and low 256 bits.
+func (z *Int) HiLo() (hi, lo *uint256.Int) {
+	return &uint256.Int{z[4], z[5], z[6], z[7]}, &uint256.Int{z[0], z[1], z[2], z[3]}
+}
+
+// FromBig converts b, reporting an overflow when b is negative or does not
+// fit in 512 bits; the result is then b mod 2^512.
+func FromBig(b *big.Int) (*Int, bool) {
+	z := &Int{}
+	overflow := z.SetFromBig(b)
+	return z, overflow
+}
+
+func (z *Int) SetFromBig(b *big.Int) bool {
+	z.Clear()
+	words := b.Bits()
+	overflow := b.BitLen() > 512
+	switch bits.UintSize {
+	case 64:
+		for i := 0; i < len(words) && i < 8; i++ {
+			z[i] = uint64(words[i])
+		}
+	case 32:
+		for i := 0; i < len(words) && i < 16; i++ {
+			z[i/2] |= uint64(words[i]) << (32 * uint(i%2))
+		}
+	}
+	if b.Sign() < 0 {
+		z.Neg(z)
+		overflow = true
+	}
+	return overflow
+}
+
+func (z *Int) ToBig() *big.Int {
+	b := z.Bytes64()
+	return new(big.Int).SetBytes(b[:])
+}
+
+// SetBytes interprets buf as a big-endian integer, keeping its last 64
+// bytes when it is longer.
+func (z *Int) SetBytes(buf []byte) *Int {
+	if len(buf) > 64 {
+		buf = buf[len(buf)-64:]
+	}
+	var b [64]byte
+	copy(b[64-len(buf):], buf)
+	for i := 0; i < 8; i++ {
+		z[i] = binary.BigEndian.Uint64(b[56-8*i:])
+	}
+	return z
+}
+
+// Bytes64 returns z as 64 big-endian bytes.
+func (z *Int) Bytes64() [64]byte {
+	var b [64]byte
+	for i := 0; i < 8; i++ {
+		binary.BigEndian.PutUint64(b[56-8*i:], z[i])
+	}
+	return b
+}
+
+// Bytes returns z as 64 big-endian bytes.
+func (z *Int) Bytes() []byte {
+	b := z.Bytes64()
+	return b[:]
+}
+
+func (z *Int) Hex() string {
+	return "0x" + hex.EncodeToString(z.Bytes())
+}
+
+func (z *Int) String() string {
+	return z.ToBig().String()
+}
+
+func (z *Int) BitLen() int {
+	for i := 7; i >= 0; i-- {
+		if z[i] != 0 {
+			return i*64 + bits.Len64(z[i])
+		}
+	}
+	return 0
+}
+
+// Cmp returns -1, 0 or 1 as x is less than, equal to or greater than y.
+func (x *Int) Cmp(y *Int) int {
+	for i := 7; i >= 0; i-- {
+		switch {
+		case x[i] < y[i]:
+			return -1
+		case x[i] > y[i]:
+			return 1
+		}
+	}
+	return 0
+}
+
+func (x *Int) Lt(y *Int) bool { return x.Cmp(y) < 0 }
+func (x *Int) Gt(y *Int) bool { return x.Cmp(y) > 0 }
+func (x *Int) Eq(y *Int) bool { return *x == *y }
+
+// AddOverflow sets z to x+y and reports whether it wrapped.
+func (z *Int) AddOverflow(x, y *Int) (*Int, bool) {
+	var carry uint64
+	for i := 0; i < 8; i++ {
+		z[i], carry = bits.Add64(x[i], y[i], carry)
+	}
+	return z, carry != 0
+}
+
+func (z *Int) Add(x, y *Int) *Int {
+	z.AddOverflow(x, y)
+	return z
+}
+
+// SubOverflow sets z to x-y and reports whether it wrapped (y > x).
+func (z *Int) SubOverflow(x, y *Int) (*Int, bool) {
+	var borrow uint64
+	for i := 0; i < 8; i++ {
+		z[i], borrow = bits.Sub64(x[i], y[i], borrow)
+	}
+	return z, borrow != 0
+}
+
+func (z *Int) Sub(x, y *Int) *Int {
+	z.SubOverflow(x, y)
+	return z
+}
+
+// Neg sets z to -x mod 2^512.
+func (z *Int) Neg(x *Int) *Int {
+	return z.Sub(&Int{}, x)
+}
+
+// umul computes the full 1024 bit product of x and y.
+func umul(x, y *Int) (p [16]uint64) {
+	for i := 0; i < 8; i++ {
+		if x[i] == 0 {
+			continue
+		}
+		var carry uint64
+		for j := 0; j < 8; j++ {
+			hi, lo := bits.Mul64(x[i], y[j])
+			var c uint64
+			lo, c = bits.Add64(lo, p[i+j], 0)
+			hi += c
+			lo, c = bits.Add64(lo, carry, 0)
+			hi += c
+			p[i+j] = lo
+			carry = hi
+		}
+		p[i+8] = carry
+	}
+	return p
+}
+
+// MulOverflow sets z to x*y and reports whether the product did not fit.
+func (z *Int) MulOverflow(x, y *Int) (*Int, bool) {
+	p := umul(x, y)
+	copy(z[:], p[:8])
+	for _, w := range p[8:] {
+		if w != 0 {
+			return z, true
+		}
+	}
+	return z, false
+}
+
+func (z *Int) Mul(x, y *Int) *Int {
+	z.MulOverflow(x, y)
+	return z
+}
+
+// Mul256 sets z to the full 512 bit product of two 256 bit integers, it
+// never overflows.
+func (z *Int) Mul256(x, y *uint256.Int) *Int {
+	return z.Mul(FromUint256(x), FromUint256(y))
+}
+
+func (z *Int) limbs() int {
+	for i := 7; i >= 0; i-- {
+		if z[i] != 0 {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// udivrem divides x by a non zero y, Knuth's algorithm D on 64 bit limbs.
+func udivrem(x, y *Int) (q, r Int) {
+	dLen := y.limbs()
+	uLen := x.limbs()
+	if uLen < dLen {
+		return Int{}, *x
+	}
+	if dLen == 1 {
+		var rem uint64
+		for i := uLen - 1; i >= 0; i-- {
+			q[i], rem = bits.Div64(rem, x[i], y[0])
+		}
+		r[0] = rem
+		return q, r
+	}
+
+	// normalise so the top limb of the divisor has its high bit set
+	s := uint(bits.LeadingZeros64(y[dLen-1]))
+	var dn [8]uint64
+	var un [9]uint64
+	for i := dLen - 1; i > 0; i-- {
+		dn[i] = y[i]<<s | y[i-1]>>(64-s)
+	}
+	dn[0] = y[0] << s
+	un[uLen] = x[uLen-1] >> (64 - s)
+	for i := uLen - 1; i > 0; i-- {
+		un[i] = x[i]<<s | x[i-1]>>(64-s)
+	}
+	un[0] = x[0] << s
+
+	dh, dl := dn[dLen-1], dn[dLen-2]
+	for j := uLen - dLen; j >= 0; j-- {
+		u2, u1, u0 := un[j+dLen], un[j+dLen-1], un[j+dLen-2]
+
+		// estimate the quotient limb, at most one too large after the
+		// correction below
+		var qhat, rhat uint64
+		overflow := false
+		if u2 >= dh {
+			qhat = ^uint64(0)
+			var c uint64
+			rhat, c = bits.Add64(u1, dh, 0)
+			overflow = c != 0
+		} else {
+			qhat, rhat = bits.Div64(u2, u1, dh)
+		}
+		for !overflow {
+			ph, pl := bits.Mul64(qhat, dl)
+			if ph < rhat || ph == rhat && pl <= u0 {
+				break
+			}
+			qhat--
+			var c uint64
+			rhat, c = bits.Add64(rhat, dh, 0)
+			overflow = c != 0
+		}
+
+		// un[j:j+dLen+1] -= qhat * dn
+		var borrow, carry uint64
+		for i := 0; i < dLen; i++ {
+			ph, pl := bits.Mul64(qhat, dn[i])
+			var c uint64
+			pl, c = bits.Add64(pl, carry, 0)
+			carry = ph + c
+			un[j+i], borrow = bits.Sub64(un[j+i], pl, borrow)
+		}
+		un[j+dLen], borrow = bits.Sub64(un[j+dLen], carry, borrow)
+
+		if borrow != 0 {
+			// qhat was one too large, add the divisor back
+			qhat--
+			var c uint64
+			for i := 0; i < dLen; i++ {
+				un[j+i], c = bits.Add64(un[j+i], dn[i], c)
+			}
+			un[j+dLen] += c
+		}
+		q[j] = qhat
+	}
+
+	for i := 0; i < dLen; i++ {
+		r[i] = un[i]>>s | un[i+1]<<(64-s)
+	}
+	return q, r
+}
+
+// DivMod sets z to x/y and m to x%y. Both are zero when y is zero.
+func (z *Int) DivMod(x, y, m *Int) (*Int, *Int) {
+	if y.IsZero() {
+		return z.Clear(), m.Clear()
+	}
+	q, r := udivrem(x, y)
+	*z, *m = q, r
+	return z, m
+}
+
+func (z *Int) Div(x, y *Int) *Int {
+	var m Int
+	z.DivMod(x, y, &m)
+	return z
+}
+
+func (z *Int) Mod(x, y *Int) *Int {
+	var q Int
+	q.DivMod(x, y, z)
+	return z
+}
+
+// Lsh sets z to x<<n, bits shifted past 2^512 are lost.
+func (z *Int) Lsh(x *Int, n uint) *Int {
+	if n >= 512 {
+		return z.Clear()
+	}
+	limbs, s := int(n/64), n%64
+	var r Int
+	for i := 7; i >= limbs; i-- {
+		r[i] = x[i-limbs] << s
+		if s > 0 && i-limbs-1 >= 0 {
+			r[i] |= x[i-limbs-1] >> (64 - s)
+		}
+	}
+	*z = r
+	return z
+}
+
+// Rsh sets z to x>>n.
+func (z *Int) Rsh(x *Int, n uint) *Int {
+	if n >= 512 {
+		return z.Clear()
+	}
+	limbs, s := int(n/64), n%64
+	var r Int
+	for i := 0; i+limbs < 8; i++ {
+		r[i] = x[i+limbs] >> s
+		if s > 0 && i+limbs+1 < 8 {
+			r[i] |= x[i+limbs+1] << (64 - s)
+		}
+	}
+	*z = r
+	return z
+}
+
+func (z *Int) And(x, y *Int) *Int {
+	for i := range z {
+		z[i] = x[i] & y[i]
+	}
+	return z
+}
+
+func (z *Int) Or(x, y *Int) *Int {
+	for i := range z {
+		z[i] = x[i] | y[i]
+	}
+	return z
+}
+
+func (z *Int) Xor(x, y *Int) *Int {
+	for i := range z {
+		z[i] = x[i] ^ y[i]
+	}
+	return z
+}
+
+func (z *Int) Not(x *Int) *Int {
+	for i := range z {
+		z[i] = ^x[i]
+	}
+	return z
+}
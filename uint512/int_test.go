@@ -0,0 +1,147 @@
+package uint512
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"lukechampine.com/frand"
+)
+
+var (
+	two512  = new(big.Int).Lsh(big.NewInt(1), 512)
+	mask512 = new(big.Int).Sub(two512, big.NewInt(1))
+)
+
+// randInt returns values with runs of zero and all-ones limbs, where carries
+// and the division corrections happen.
+func randInt() *Int {
+	var z Int
+	n := frand.Intn(9)
+	for i := 0; i < n; i++ {
+		switch frand.Intn(4) {
+		case 0:
+			z[i] = ^uint64(0)
+		case 1:
+			z[i] = 0
+		default:
+			z[i] = frand.Uint64n(^uint64(0))
+		}
+	}
+	return &z
+}
+
+func fromBytes(b []byte) *Int {
+	return new(Int).SetBytes(b)
+}
+
+func checkOps(t *testing.T, x, y *Int) {
+	t.Helper()
+	bx, by := x.ToBig(), y.ToBig()
+
+	expect := func(op string, got *Int, want *big.Int, overflow bool) {
+		t.Helper()
+		wrapped := new(big.Int).And(want, mask512)
+		if got.ToBig().Cmp(wrapped) != 0 {
+			t.Fatalf("%s %x %x: got %s want %s", op, bx, by, got.ToBig().Text(16), wrapped.Text(16))
+		}
+		if overflow != (want.Sign() < 0 || want.Cmp(mask512) > 0) {
+			t.Fatalf("%s %x %x: overflow flag %v", op, bx, by, overflow)
+		}
+	}
+
+	z, o := new(Int).AddOverflow(x, y)
+	expect("add", z, new(big.Int).Add(bx, by), o)
+	z, o = new(Int).SubOverflow(x, y)
+	expect("sub", z, new(big.Int).Sub(bx, by), o)
+	z, o = new(Int).MulOverflow(x, y)
+	expect("mul", z, new(big.Int).Mul(bx, by), o)
+
+	if by.Sign() != 0 {
+		q, m := new(Int).DivMod(x, y, new(Int))
+		expect("div", q, new(big.Int).Div(bx, by), false)
+		expect("mod", m, new(big.Int).Mod(bx, by), false)
+	}
+	if c := x.Cmp(y); c != bx.Cmp(by) {
+		t.Fatalf("cmp %x %x: got %d", bx, by, c)
+	}
+
+	n := uint(y[0] % 530)
+	expect("lsh", new(Int).Lsh(x, n), new(big.Int).And(new(big.Int).Lsh(bx, n), mask512), false)
+	expect("rsh", new(Int).Rsh(x, n), new(big.Int).Rsh(bx, n), false)
+}
+
+func TestIntAgainstBig(t *testing.T) {
+	for i := 0; i < 20000; i++ {
+		checkOps(t, randInt(), randInt())
+	}
+}
+
+func TestIntAliasing(t *testing.T) {
+	x := randInt()
+	want := new(Int).Mul(x, x)
+	if x.Mul(x, x); !x.Eq(want) {
+		t.Fatal("Mul with z == x == y")
+	}
+	y := randInt()
+	if y.IsZero() {
+		y.SetUint64(3)
+	}
+	want = new(Int).Div(x, y)
+	if x.Div(x, y); !x.Eq(want) {
+		t.Fatal("Div with z == x")
+	}
+}
+
+func TestMul256(t *testing.T) {
+	for i := 0; i < 1000; i++ {
+		a := new(uint256.Int).SetBytes(frand.Bytes(32))
+		b := new(uint256.Int).SetBytes(frand.Bytes(32))
+		want := new(big.Int).Mul(a.ToBig(), b.ToBig())
+		if got := new(Int).Mul256(a, b); got.ToBig().Cmp(want) != 0 {
+			t.Fatalf("%s * %s", a, b)
+		}
+		hi, lo := new(Uint512).Mul(a, b)
+		if FromHiLo(hi, lo).ToBig().Cmp(want) != 0 {
+			t.Fatal("Uint512.Mul does not return the full product")
+		}
+	}
+}
+
+func TestIntConversions(t *testing.T) {
+	x := randInt()
+	b := x.ToBig()
+	y, overflow := FromBig(b)
+	if overflow || !x.Eq(y) {
+		t.Fatal("big.Int round trip")
+	}
+	if _, overflow := FromBig(two512); !overflow {
+		t.Fatal("2^512 does not overflow")
+	}
+	if z, overflow := FromBig(big.NewInt(-1)); !overflow || z.BitLen() != 512 {
+		t.Fatal("-1 does not wrap to 2^512-1")
+	}
+	if !fromBytes(x.Bytes()).Eq(x) {
+		t.Fatal("bytes round trip")
+	}
+
+	hi, lo := x.HiLo()
+	s := &Uint512{hi, lo}
+	if !s.Merge().Eq(x) || !x.ToUint512Struct().Merge().Eq(x) {
+		t.Fatal("Uint512 round trip")
+	}
+	low, truncated := x.Uint256()
+	if truncated != (x.BitLen() > 256) || !FromUint256(low).Eq(new(Int).And(x, FromHiLo(new(uint256.Int), new(uint256.Int).SetAllOne()))) {
+		t.Fatal("uint256 conversion")
+	}
+}
+
+func FuzzIntOps(f *testing.F) {
+	f.Add(make([]byte, 64), make([]byte, 64))
+	f.Add(mask512.Bytes(), []byte{1})
+	f.Add(mask512.Bytes(), mask512.Bytes())
+	f.Add(new(big.Int).Lsh(big.NewInt(1), 511).Bytes(), []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff})
+	f.Fuzz(func(t *testing.T, a, b []byte) {
+		checkOps(t, fromBytes(a), fromBytes(b))
+	})
+}
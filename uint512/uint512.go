@@ -1,9 +1,9 @@
 package uint512
 
 import (
-	"bytes"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"github.com/holiman/uint256"
 	"github.com/quantosnetwork/Quantos/crypto"
 	"github.com/zeebo/blake3"
@@ -15,22 +15,17 @@ import (
 	"time"
 )
 
+// Uint512 is a 512 bit value as its high (a) and low (b) 256 bit halves.
 type Uint512 struct {
 	a, b *uint256.Int
 }
 
-type Int struct {
-	b []byte
-}
-
+// NewUint512FromBytes builds the value from its big-endian high and low
+// halves.
 func NewUint512FromBytes(a1, b1 []byte) *Uint512 {
-
-	ab1 := ToBigInt(a1)
-	ab2 := ToBigInt(b1)
-	u1, _ := uint256.FromBig(ab1)
-	u2, _ := uint256.FromBig(ab2)
 	return &Uint512{
-		u1, u2,
+		new(uint256.Int).SetBytes(a1),
+		new(uint256.Int).SetBytes(b1),
 	}
 }
 
@@ -42,35 +37,18 @@ func ToBigInt(bb []byte) *big.Int {
 
 }
 
+// Mul returns the high and low 256 bits of the full product a*b.
 func (u512 *Uint512) Mul(a, b *uint256.Int) (r0, r1 *uint256.Int) {
-	mmu := uint256.NewInt(0)
-	mm := mmu.MulMod(a, b, uint256.NewInt(1))
-	r0 = mmu.Mul(a, b)
-	r1 = mmu.Sub(mm, r0)
-	r0.Lt(mm)
-	return
+	return new(Int).Mul256(a, b).HiLo()
 }
 
 func (u512 *Uint512) Merge() *Int {
-	buf := make([][]byte, 2)
-	buf[0] = u512.a.Bytes()
-	buf[1] = u512.b.Bytes()
-	nInt := bytes.Join(buf, nil)
-	var bufInt Int
-	copy(bufInt.b, nInt[:])
-	return &bufInt
+	return FromHiLo(u512.a, u512.b)
 }
 
 func (uInt *Int) ToUint512Struct() *Uint512 {
-
-	a := uInt.Bytes()[0:255]
-	b := uInt.Bytes()[255:]
-	bb := new(big.Int).SetBytes(a[:])
-	ba := new(big.Int).SetBytes(b[:])
-	bba, _ := uint256.FromBig(bb)
-	bbb, _ := uint256.FromBig(ba)
-	return &Uint512{bba, bbb}
-
+	hi, lo := uInt.HiLo()
+	return &Uint512{hi, lo}
 }
 
 func NewIntFromUint64s(a, b uint64) (*Uint512, *Int) {
@@ -90,6 +68,7 @@ func NewBlankUint512() (*Uint512, *Int) {
 
 }
 
+// FromBig merges a high and a low half, each reduced modulo 2^256.
 func (u512 *Uint512) FromBig(a, b *big.Int) *Int {
 	aa, _ := uint256.FromBig(a)
 	bb, _ := uint256.FromBig(b)
@@ -107,10 +86,6 @@ func (uInt *Int) ToString() string {
 	return "0x" + uInt.ToHex()
 }
 
-func (uInt *Int) Bytes() []byte {
-	return uInt.b
-}
-
 func (uInt *Int) Hash() []byte {
 	hasher := blake3.Hasher{}
 	hasher.Write(uInt.Bytes()[:])
@@ -155,6 +130,10 @@ type Address struct {
 	pk              []byte
 	sk              []byte
 	ssk             []byte
+	chainCode       []byte
+	depth           uint8
+	parentFP        [4]byte
+	index           uint32
 	group           kyber.Group
 	suite           *edwards25519.SuiteEd25519
 	Signature       []byte
@@ -163,58 +142,133 @@ type Address struct {
 }
 
 func (addr *address) Create() *Address {
-	seed := make([]byte, 512)
+	seed := make([]byte, crypto.MaxSeedBytes)
 	frand.Read(seed)
-	seedA := seed[:255]
-	seedB := seed[255:]
-	u := new(Uint512)
-	sab := new(big.Int).SetBytes(seedA)
-	sbb := new(big.Int).SetBytes(seedB)
-	u.a, _ = uint256.FromBig(sab)
-	u.b, _ = uint256.FromBig(sbb)
-	add := new(Address)
-	add.Raw = &address{u}
+	add, _ := NewMasterAddress(seed)
+	return add
+}
+
+// NewMasterAddress creates the root address of an HD wallet from a seed,
+// e.g. a BIP39 seed. The same seed always gives the same keys.
+func NewMasterAddress(seed []byte) (*Address, error) {
+	ext, err := crypto.NewMasterExtendedKey(seed)
+	if err != nil {
+		return nil, err
+	}
+	return newAddressFromExtendedKey(ext)
+}
+
+func newAddressFromExtendedKey(ext *crypto.ExtendedKey) (*Address, error) {
+	k, err := ext.HardenedKeys()
+	if err != nil {
+		return nil, err
+	}
+	add := newAddressFromKeys(k)
+	add.chainCode = append([]byte(nil), ext.ChainCode...)
+	add.depth = ext.Depth
+	add.parentFP = ext.ParentFingerprint
+	add.index = ext.Index
+	return add, nil
+}
+
+// AddressFromPrivateKey imports a private key exported with PrivateKey.
+// The address has no chain code, so it can not derive children.
+func AddressFromPrivateKey(sk []byte) (*Address, error) {
+	k, err := crypto.HardenedKeysFromPrivateKey(sk)
+	if err != nil {
+		return nil, err
+	}
+	return newAddressFromKeys(k), nil
+}
 
-	k := crypto.GenerateHardenedKeys()
+func newAddressFromKeys(k *crypto.HardenedKeys) *Address {
+	add := new(Address)
 	add.pk, _ = k.PubKey.MarshalBinary()
 	add.sk, _ = k.PrivKey.MarshalBinary()
 	add.group = k.Group
 	add.suite = k.Suite
-	_ = k.PrivKey.UnmarshalBinary(add.sk)
+	add.Raw = &address{rawFromPublicKey(add.pk)}
 
 	// we sign the public key
 	now := time.Now().UnixNano()
-	pubbytes, _ := k.PubKey.MarshalBinary()
-	add.Signature = k.Sign(pubbytes)
-	n256 := uint256.NewInt(uint64(now))
-	add.TimestampSigned = k.Sign(n256.Bytes())
+	add.Signature = k.Sign(add.pk)
+	add.TimestampSigned = k.Sign(timestampMessage(now))
 	add.Timestamp = now
 	return add
 }
 
-func (addr *Address) Serialize() []byte {
+// rawFromPublicKey binds the raw value to the public key so it is
+// reproducible.
+func rawFromPublicKey(pk []byte) *Uint512 {
+	raw := make([]byte, 64)
+	blake3.DeriveKey("qbit-address-raw", pk, raw)
+	return NewUint512FromBytes(raw[:32], raw[32:])
+}
 
-	toSerialize := addr.Raw.a.String() + "-" + hex.EncodeToString(addr.pk) + "-" + hex.EncodeToString(addr.
-		Signature) + "-" + hex.
-		EncodeToString(addr.TimestampSigned)
-	return []byte(toSerialize)
+func timestampMessage(ts int64) []byte {
+	return uint256.NewInt(uint64(ts)).Bytes()
 }
 
-func (addr *Address) Master() []byte {
-	privateKey := addr.sk
-	keyed, _ := blake3.NewKeyed(privateKey)
+// ExtendedKey returns the HD key of the address.
+func (addr *Address) ExtendedKey() (*crypto.ExtendedKey, error) {
+	if len(addr.chainCode) == 0 {
+		return nil, errors.New("quantos address: address has no chain code")
+	}
+	sk := addr.suite.Scalar()
+	if err := sk.UnmarshalBinary(addr.sk); err != nil {
+		return nil, err
+	}
+	pk := addr.suite.Point()
+	if err := pk.UnmarshalBinary(addr.pk); err != nil {
+		return nil, err
+	}
+	return &crypto.ExtendedKey{
+		PrivKey:           sk,
+		PubKey:            pk,
+		ChainCode:         append([]byte(nil), addr.chainCode...),
+		Depth:             addr.depth,
+		ParentFingerprint: addr.parentFP,
+		Index:             addr.index,
+	}, nil
+}
+
+// Serialize returns the binary encoding of the public part of the
+// address, see encoding.go.
+func (addr *Address) Serialize() []byte {
+	b, _ := addr.MarshalBinary()
+	return b
+}
+
+// Master returns the master value of the address, its encoding hashed
+// under the private key. Loaded addresses have no private key and give
+// ErrNoPrivateKey.
+func (addr *Address) Master() ([]byte, error) {
+	if len(addr.sk) != 32 {
+		return nil, ErrNoPrivateKey
+	}
+	keyed, err := blake3.NewKeyed(addr.sk)
+	if err != nil {
+		return nil, err
+	}
 	keyed.Write(addr.Serialize())
-	return keyed.Sum(nil)
+	return keyed.Sum(nil), nil
+}
 
+// Derive returns the child address at path (see crypto.ParseDerivationPath).
+// Deriving the same path from the same master always gives the same address.
+func (addr *Address) Derive(path []uint32) (*Address, error) {
+	ext, err := addr.ExtendedKey()
+	if err != nil {
+		return nil, err
+	}
+	child, err := ext.DerivePath(path)
+	if err != nil {
+		return nil, err
+	}
+	return newAddressFromExtendedKey(child)
 }
 
-func (addr *Address) Derive() string {
-	buf := make([]byte, 32)
-	frand.Read(buf)
-	bb := new(big.Int).SetBytes(buf).String()
-	walletBytes := make([]byte, 40)
-	blake3.DeriveKey("qbit-address-"+bb, addr.Serialize(), walletBytes)
-	wbb := new(big.Int).SetBytes(walletBytes)
-	u, _ := uint256.FromBig(wbb)
-	return u.String()
+// PublicKey returns the marshalled public key of the address.
+func (addr *Address) PublicKey() []byte {
+	return addr.pk
 }